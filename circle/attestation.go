@@ -40,14 +40,22 @@ func CheckAttestation(attestationURL string, logger log.Logger, irisLookupID str
 	rawResponse, err := client.Do(req)
 	if err != nil {
 		logger.Debug("error during request: " + err.Error())
+		health.recordFailure(err)
 		return nil
 	}
 
 	defer rawResponse.Body.Close()
 	if rawResponse.StatusCode != http.StatusOK {
 		logger.Debug("non 200 response received from Circles attestation API")
+		// not found only means the attestation is not available yet
+		if rawResponse.StatusCode == http.StatusNotFound {
+			health.recordSuccess()
+		} else {
+			health.recordFailure(fmt.Errorf("attestation api returned status %d", rawResponse.StatusCode))
+		}
 		return nil
 	}
+	health.recordSuccess()
 
 	body, err := io.ReadAll(rawResponse.Body)
 	if err != nil {
@@ -0,0 +1,75 @@
+package circle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// unhealthyAfterFailures is how many consecutive failed attestation api calls mark the api as unhealthy
+const unhealthyAfterFailures = 5
+
+// health tracks the outcome of recent calls to the attestation api
+var health = &healthTracker{}
+
+type healthTracker struct {
+	mu                  sync.Mutex
+	consecutiveFailures int
+	lastSuccess         time.Time
+	lastErr             error
+}
+
+func (h *healthTracker) recordSuccess() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.consecutiveFailures = 0
+	h.lastSuccess = time.Now()
+	h.lastErr = nil
+}
+
+func (h *healthTracker) recordFailure(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.consecutiveFailures++
+	h.lastErr = err
+}
+
+// CheckHealth returns an error if the most recent calls to the attestation api have all failed.
+// The api is considered healthy until it has been called.
+func CheckHealth() error {
+	health.mu.Lock()
+	defer health.mu.Unlock()
+
+	if health.consecutiveFailures < unhealthyAfterFailures {
+		return nil
+	}
+	if health.lastSuccess.IsZero() {
+		return fmt.Errorf("last %d attestation api calls failed, last error: %w", health.consecutiveFailures, health.lastErr)
+	}
+	return fmt.Errorf("last %d attestation api calls failed since %s, last error: %w",
+		health.consecutiveFailures, health.lastSuccess.Format(time.RFC3339), health.lastErr)
+}
+
+// CheckReachable ensures the attestation api responds. Any response that is not a server error counts as reachable,
+// as the base url itself is not an attestation.
+func CheckReachable(ctx context.Context, attestationURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, attestationURL, nil)
+	if err != nil {
+		return fmt.Errorf("unable to create request: %w", err)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach attestation api: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("attestation api returned status %d", res.StatusCode)
+	}
+	return nil
+}
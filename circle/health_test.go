@@ -0,0 +1,38 @@
+package circle_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/circle"
+)
+
+func TestCheckHealth(t *testing.T) {
+	status := http.StatusNotFound
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	// reset failures recorded by other tests
+	circle.CheckAttestation(server.URL, logger, "1234", "", 0, 4)
+	require.NoError(t, circle.CheckHealth())
+
+	status = http.StatusInternalServerError
+	// a few failures are tolerated
+	circle.CheckAttestation(server.URL, logger, "1234", "", 0, 4)
+	require.NoError(t, circle.CheckHealth())
+
+	for i := 0; i < 5; i++ {
+		circle.CheckAttestation(server.URL, logger, "1234", "", 0, 4)
+	}
+	require.Error(t, circle.CheckHealth())
+
+	// not found means the api is reachable but the attestation is not ready yet
+	status = http.StatusNotFound
+	circle.CheckAttestation(server.URL, logger, "1234", "", 0, 4)
+	require.NoError(t, circle.CheckHealth())
+}
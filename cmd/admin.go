@@ -0,0 +1,266 @@
+package cmd
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+// envAdminToken overrides the admin token set in the config
+const envAdminToken = "RELAYER_ADMIN_TOKEN"
+
+// pausedRoutes holds the enabled routes an operator paused through the admin API
+var pausedRoutes = types.NewPausedRoutes()
+
+// heldTxs maps source tx hash -> txs waiting for a paused route to be resumed
+var heldTxs = struct {
+	sync.Mutex
+	txs map[string]*types.TxState
+}{txs: make(map[string]*types.TxState)}
+
+// holdTx parks a tx whose messages are on a paused route. It is not retried until the route is resumed.
+func holdTx(logger log.Logger, tx *types.TxState) {
+	heldTxs.Lock()
+	defer heldTxs.Unlock()
+	heldTxs.txs[tx.TxHash] = tx
+	logger.Info("Holding tx until its route is resumed", "tx", tx.TxHash)
+}
+
+// releaseHeldTxs places every held tx back on the processing queue.
+// Txs that still have messages on a paused route are held again by the processor.
+func releaseHeldTxs(processingQueue chan *types.TxState) int {
+	heldTxs.Lock()
+	txs := heldTxs.txs
+	heldTxs.txs = make(map[string]*types.TxState)
+	heldTxs.Unlock()
+
+	for _, tx := range txs {
+		processingQueue <- tx
+	}
+	return len(txs)
+}
+
+// adminToken returns the bearer token required by the admin API, preferring the env variable over the config.
+func adminToken(cfg *types.Config) string {
+	if token := os.Getenv(envAdminToken); token != "" {
+		return token
+	}
+	return cfg.API.AdminToken
+}
+
+// bearerAuth rejects requests that do not carry the token in an "Authorization: Bearer <token>" header.
+func bearerAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": "unauthorized"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// adminAPI serves the operator endpoints under /admin
+type adminAPI struct {
+	cfg               *types.Config
+	logger            log.Logger
+	registeredDomains map[types.Domain]types.Chain
+	processingQueue   chan *types.TxState
+}
+
+func (a *adminAPI) register(router *gin.RouterGroup) {
+	router.GET("/dlq", getDeadLetters)
+	router.POST("/dlq/:txHash/replay", a.replayDeadLetter)
+	router.POST("/relay", a.relayTx)
+	router.GET("/routes", a.getRoutes)
+	router.POST("/routes/:sourceDomain/:destDomain/pause", a.pauseRoute)
+	router.POST("/routes/:sourceDomain/:destDomain/resume", a.resumeRoute)
+	router.POST("/messages/iris/:irisLookupID/status", a.forceStatus)
+	router.POST("/flush/:domain", a.flush)
+}
+
+func (a *adminAPI) replayDeadLetter(c *gin.Context) {
+	tx, err := replayDeadLetter(c.Param("txHash"), a.processingQueue)
+	switch {
+	case errors.Is(err, errNotDeadLettered):
+		c.JSON(http.StatusNotFound, gin.H{"message": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"message": err.Error()})
+	default:
+		c.JSON(http.StatusOK, tx.Msgs)
+	}
+}
+
+type relayRequest struct {
+	Domain types.Domain `json:"domain"`
+	TxHash string       `json:"tx_hash"`
+}
+
+// relayTx fetches a source tx from its chain and places its CCTP messages on the processing queue.
+func (a *adminAPI) relayTx(c *gin.Context) {
+	var req relayRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.TxHash == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "expected a json body with domain and tx_hash"})
+		return
+	}
+
+	chain, ok := a.registeredDomains[req.Domain]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("no chain registered for domain %d", req.Domain)})
+		return
+	}
+
+	tx, err := chain.QueryTxMessages(c.Request.Context(), a.logger, req.TxHash)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"message": err.Error()})
+		return
+	}
+
+	if existing, ok := State.Load(tx.TxHash); ok {
+		c.JSON(http.StatusConflict, gin.H{"message": "tx is already tracked", "msgs": existing.Msgs})
+		return
+	}
+
+	a.logger.Info("Relaying tx enqueued through the admin api", "tx", tx.TxHash, "domain", req.Domain)
+	a.processingQueue <- tx
+	c.JSON(http.StatusAccepted, tx.Msgs)
+}
+
+func (a *adminAPI) getRoutes(c *gin.Context) {
+	var enabled []types.Route
+	for source, dests := range a.cfg.EnabledRoutes {
+		for _, dest := range dests {
+			enabled = append(enabled, types.Route{SourceDomain: source, DestDomain: dest})
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"enabled": enabled, "paused": pausedRoutes.List()})
+}
+
+func (a *adminAPI) pauseRoute(c *gin.Context) {
+	route, ok := a.parseRoute(c)
+	if !ok {
+		return
+	}
+	pausedRoutes.Pause(route)
+	a.logger.Info("Paused route through the admin api", "source_domain", route.SourceDomain, "dest_domain", route.DestDomain)
+	c.JSON(http.StatusOK, gin.H{"paused": pausedRoutes.List()})
+}
+
+func (a *adminAPI) resumeRoute(c *gin.Context) {
+	route, ok := a.parseRoute(c)
+	if !ok {
+		return
+	}
+	pausedRoutes.Resume(route)
+	released := releaseHeldTxs(a.processingQueue)
+	a.logger.Info("Resumed route through the admin api", "source_domain", route.SourceDomain, "dest_domain", route.DestDomain, "released_txs", released)
+	c.JSON(http.StatusOK, gin.H{"paused": pausedRoutes.List()})
+}
+
+// parseRoute parses the route from the path params and ensures it is an enabled route.
+func (a *adminAPI) parseRoute(c *gin.Context) (types.Route, bool) {
+	source, err := strconv.ParseUint(c.Param("sourceDomain"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse source domain"})
+		return types.Route{}, false
+	}
+	dest, err := strconv.ParseUint(c.Param("destDomain"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse dest domain"})
+		return types.Route{}, false
+	}
+
+	route := types.Route{SourceDomain: types.Domain(source), DestDomain: types.Domain(dest)}
+	for _, d := range a.cfg.EnabledRoutes[route.SourceDomain] {
+		if d == route.DestDomain {
+			return route, true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("route from %d to %d is not enabled", source, dest)})
+	return types.Route{}, false
+}
+
+type forceStatusRequest struct {
+	Status string `json:"status"`
+}
+
+// forceStatus overrides the status of a single message. Only complete and failed can be forced.
+func (a *adminAPI) forceStatus(c *gin.Context) {
+	var req forceStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Status != types.Complete && req.Status != types.Failed) {
+		c.JSON(http.StatusBadRequest, gin.H{"message": fmt.Sprintf("expected a json body with status %q or %q", types.Complete, types.Failed)})
+		return
+	}
+
+	id := strings.TrimPrefix(strings.ToLower(c.Param("irisLookupID")), "0x")
+
+	var tx *types.TxState
+	var msg *types.MessageState
+	State.Range(func(_ string, t *types.TxState) bool {
+		for _, m := range t.Msgs {
+			if strings.EqualFold(m.IrisLookupID, id) {
+				tx, msg = t, m
+				return false
+			}
+		}
+		return true
+	})
+	if msg == nil {
+		c.JSON(http.StatusNotFound, gin.H{"message": "message not found"})
+		return
+	}
+
+	snapshot := statusSnapshot(tx)
+	State.Mu.Lock()
+	previous := msg.Status
+	msg.Status = req.Status
+	msg.Updated = time.Now()
+	State.Mu.Unlock()
+	publishTransitions(tx, snapshot)
+
+	if err := State.Store(tx.TxHash, tx); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"message": fmt.Sprintf("unable to persist tx state: %s", err)})
+		return
+	}
+
+	// nothing left to replay once every message is settled
+	if _, ok := deadLetters.Load(tx.TxHash); ok && tx.IsTerminal() {
+		if err := deadLetters.Remove(tx.TxHash); err != nil {
+			a.logger.Error("Unable to remove tx from the dead letter queue", "tx", tx.TxHash, "err", err)
+		}
+	}
+
+	a.logger.Info("Forced message status through the admin api", "tx", tx.TxHash, "iris_lookup_id", msg.IrisLookupID, "previous_status", previous, "status", req.Status)
+	c.JSON(http.StatusOK, tx.Msgs)
+}
+
+// flush triggers an immediate flush of the chain with the given domain.
+func (a *adminAPI) flush(c *gin.Context) {
+	domain, err := strconv.ParseUint(c.Param("domain"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse domain"})
+		return
+	}
+
+	chain, ok := a.registeredDomains[types.Domain(domain)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"message": fmt.Sprintf("no chain registered for domain %d", domain)})
+		return
+	}
+
+	chain.TriggerFlush()
+	a.logger.Info("Triggered flush through the admin api", "chain", chain.Name(), "domain", domain)
+	c.JSON(http.StatusAccepted, gin.H{"message": fmt.Sprintf("flush triggered for %s", chain.Name())})
+}
@@ -0,0 +1,303 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// messagePage is the response of the paginated message list endpoint
+type messagePage struct {
+	Total    int                  `json:"total"`
+	Limit    int                  `json:"limit"`
+	Offset   int                  `json:"offset"`
+	Messages []types.MessageState `json:"messages"`
+}
+
+// listenAPI binds the configured API address, so that an unavailable address fails the start command
+// before any chain is started.
+func listenAPI(cfg types.APISettings) (net.Listener, error) {
+	listener, err := net.Listen("tcp", cfg.ListenAddr())
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen on %s: %w", cfg.ListenAddr(), err)
+	}
+	return listener, nil
+}
+
+// serveAPI serves the API on the listener until the context is done.
+func serveAPI(
+	ctx context.Context,
+	a *AppState,
+	listener net.Listener,
+	processingQueue chan *types.TxState,
+	registeredDomains map[types.Domain]types.Chain,
+) error {
+	logger := a.Logger
+	cfg := a.Config
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.Default()
+
+	err := router.SetTrustedProxies(cfg.API.TrustedProxies) // vpn.primary.strange.love
+	if err != nil {
+		return fmt.Errorf("unable to set trusted proxies on API server: %w", err)
+	}
+
+	if len(cfg.API.CORSOrigins) > 0 {
+		router.Use(cors(cfg.API.CORSOrigins))
+	}
+
+	router.GET("/healthz", getHealthz)
+	router.GET("/readyz", getReadyz(registeredDomains))
+	router.GET("/tx/:txHash", getTxByHash)
+	router.GET("/messages", getMessages)
+	router.GET("/messages/iris/:irisLookupID", getMessageByIrisLookupID)
+	router.GET("/messages/nonce/:sourceDomain/:nonce", getMessageByNonce)
+	router.GET("/events", streamEvents)
+
+	if token := adminToken(cfg); token != "" {
+		admin := &adminAPI{
+			cfg:               cfg,
+			logger:            logger,
+			registeredDomains: registeredDomains,
+			processingQueue:   processingQueue,
+		}
+		admin.register(router.Group("/admin", bearerAuth(token)))
+	} else {
+		logger.Info(fmt.Sprintf("No admin token configured. The admin api is disabled, set api.admin-token or %s to enable it", envAdminToken))
+	}
+
+	server := &http.Server{
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Error shutting down API server", "err", err)
+		}
+	}()
+
+	if cfg.API.TLSCertFile != "" {
+		logger.Info("Serving API over https", "address", listener.Addr().String())
+		err = server.ServeTLS(listener, cfg.API.TLSCertFile, cfg.API.TLSKeyFile)
+	} else {
+		logger.Info("Serving API", "address", listener.Addr().String())
+		err = server.Serve(listener)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// cors allows browsers on the given origins to call the API. An origin of "*" allows any origin.
+func cors(origins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin != "" && (allowed["*"] || allowed[origin]) {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Authorization, Content-Type, Last-Event-ID")
+			c.Header("Vary", "Origin")
+		}
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+func getTxByHash(c *gin.Context) {
+	txHash := c.Param("txHash")
+
+	domain := c.Query("domain")
+	domainInt, err := strconv.ParseInt(domain, 10, 32)
+	if domain != "" && err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse domain"})
+		return
+	}
+
+	if tx, ok := State.Load(txHash); ok &&
+		(domain == "" || len(tx.Msgs) > 0 && tx.Msgs[0].SourceDomain == types.Domain(uint32(domainInt))) {
+		c.JSON(http.StatusOK, tx.Msgs)
+		return
+	}
+
+	// evicted tx states may still be in the archive
+	if archive != nil {
+		archived, err := archive.Find(txHash)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"message": "unable to search archive"})
+			return
+		}
+		if archived != nil && len(archived.TxState.Msgs) > 0 &&
+			(domain == "" || archived.TxState.Msgs[0].SourceDomain == types.Domain(uint32(domainInt))) {
+			c.JSON(http.StatusGone, gin.H{
+				"message":     "message has been archived",
+				"archived_at": archived.ArchivedAt,
+				"msgs":        archived.TxState.Msgs,
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusNotFound, gin.H{"message": "message not found"})
+}
+
+// getMessages lists messages from the State, oldest first.
+//
+// Query params (all optional):
+//   - status: may be repeated or comma separated, ex: status=pending,attested
+//   - source_domain, dest_domain
+//   - created_after, created_before, updated_after, updated_before: RFC3339 timestamps
+//   - limit (default 100, max 1000), offset
+func getMessages(c *gin.Context) {
+	filter, err := parseMessageFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": err.Error()})
+		return
+	}
+
+	limit, err := parseQueryInt(c, "limit", defaultPageLimit)
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse limit"})
+		return
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	offset, err := parseQueryInt(c, "offset", 0)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse offset"})
+		return
+	}
+
+	msgs := State.Messages(filter)
+	page := messagePage{
+		Total:    len(msgs),
+		Limit:    limit,
+		Offset:   offset,
+		Messages: []types.MessageState{},
+	}
+	if offset < len(msgs) {
+		end := offset + limit
+		if end > len(msgs) {
+			end = len(msgs)
+		}
+		page.Messages = msgs[offset:end]
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// getMessageByIrisLookupID returns the message with the given iris lookup id, with or without the 0x prefix
+func getMessageByIrisLookupID(c *gin.Context) {
+	id := strings.TrimPrefix(strings.ToLower(c.Param("irisLookupID")), "0x")
+
+	msg, ok := State.FindMessage(func(msg *types.MessageState) bool {
+		return strings.EqualFold(msg.IrisLookupID, id)
+	})
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"message": "message not found"})
+		return
+	}
+	c.JSON(http.StatusOK, msg)
+}
+
+// getMessageByNonce returns the message with the given source domain and nonce.
+// The pair uniquely identifies a CCTP message.
+func getMessageByNonce(c *gin.Context) {
+	domain, err := strconv.ParseUint(c.Param("sourceDomain"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse source domain"})
+		return
+	}
+	nonce, err := strconv.ParseUint(c.Param("nonce"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse nonce"})
+		return
+	}
+
+	msg, ok := State.FindMessage(func(msg *types.MessageState) bool {
+		return msg.SourceDomain == types.Domain(domain) && msg.Nonce == nonce
+	})
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"message": "message not found"})
+		return
+	}
+	c.JSON(http.StatusOK, msg)
+}
+
+func parseMessageFilter(c *gin.Context) (types.MessageFilter, error) {
+	var filter types.MessageFilter
+
+	for _, status := range c.QueryArray("status") {
+		for _, s := range strings.Split(status, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				filter.Statuses = append(filter.Statuses, s)
+			}
+		}
+	}
+
+	for param, dest := range map[string]**types.Domain{
+		"source_domain": &filter.SourceDomain,
+		"dest_domain":   &filter.DestDomain,
+	} {
+		if v := c.Query(param); v != "" {
+			d, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				return filter, fmt.Errorf("unable to parse %s", param)
+			}
+			domain := types.Domain(d)
+			*dest = &domain
+		}
+	}
+
+	for param, dest := range map[string]*time.Time{
+		"created_after":  &filter.CreatedAfter,
+		"created_before": &filter.CreatedBefore,
+		"updated_after":  &filter.UpdatedAfter,
+		"updated_before": &filter.UpdatedBefore,
+	} {
+		if v := c.Query(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return filter, fmt.Errorf("unable to parse %s, expected an RFC3339 timestamp", param)
+			}
+			*dest = t
+		}
+	}
+
+	return filter, nil
+}
+
+func parseQueryInt(c *gin.Context, param string, defaultValue int) (int, error) {
+	v := c.Query(param)
+	if v == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(v)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/rs/zerolog"
 
 	"cosmossdk.io/log"
@@ -26,6 +27,9 @@ type AppState struct {
 	LogLevel string
 
 	Logger log.Logger
+
+	// DryRun simulates mints on the destination chains instead of broadcasting them
+	DryRun bool
 }
 
 func NewAppState() *AppState {
@@ -104,6 +108,12 @@ func (a *AppState) validateConfig() error {
 			if err != nil {
 				return err
 			}
+			if cc.GasAdjustment < 0 {
+				return fmt.Errorf("chain %s: gas-adjustment must not be negative", name)
+			}
+			if cc.BatchWindow < 0 || cc.BatchMaxMsgs < 0 {
+				return fmt.Errorf("chain %s: batch-window and batch-max-msgs must not be negative", name)
+			}
 		} else {
 			// validate eth based chains
 			cc := cfg.(*ethereum.ChainConfig)
@@ -120,6 +130,21 @@ func (a *AppState) validateConfig() error {
 			if err != nil {
 				return err
 			}
+			if cc.BumpTimeout > 0 && cc.MaxFeeGwei == 0 {
+				return fmt.Errorf("chain %s: max-fee-gwei must be set when bump-timeout is", name)
+			}
+			if cc.FeeMode != "" && cc.FeeMode != ethereum.FeeModeDynamic && cc.FeeMode != ethereum.FeeModeLegacy {
+				return fmt.Errorf("chain %s: fee-mode must be %q or %q", name, ethereum.FeeModeDynamic, ethereum.FeeModeLegacy)
+			}
+			if cc.TipMultiplier < 0 || cc.FeeMultiplier < 0 {
+				return fmt.Errorf("chain %s: tip-multiplier and fee-multiplier must not be negative", name)
+			}
+			if cc.Multicall != "" && !common.IsHexAddress(cc.Multicall) {
+				return fmt.Errorf("chain %s: multicall must be a contract address", name)
+			}
+			if cc.MulticallWindow < 0 || cc.MulticallMaxMsgs < 0 {
+				return fmt.Errorf("chain %s: multicall-window and multicall-max-msgs must not be negative", name)
+			}
 		}
 	}
 
@@ -134,6 +159,32 @@ func (a *AppState) validateConfig() error {
 		return err
 	}
 
+	// validate state config
+	err = a.validateStateConfig()
+	if err != nil {
+		return err
+	}
+
+	// validate api config
+	err = a.validateAPIConfig()
+	if err != nil {
+		return err
+	}
+
+	// validate reconcile config
+	reconcile := a.Config.Reconcile
+	if reconcile.Interval < 0 || reconcile.Window < 0 || reconcile.Threshold < 0 {
+		return fmt.Errorf("reconcile interval, window and threshold cannot be negative in the config")
+	}
+	for name, window := range reconcile.Windows {
+		if _, ok := a.Config.Chains[name]; !ok {
+			return fmt.Errorf("reconcile window set for chain %q, which is not in the config", name)
+		}
+		if window < 0 {
+			return fmt.Errorf("reconcile window of chain %q cannot be negative in the config", name)
+		}
+	}
+
 	// validate processor worker count
 	if a.Config.ProcessorWorkerCount == 0 {
 		return fmt.Errorf("ProcessorWorkerCount must be greater than zero in the config")
@@ -203,3 +254,32 @@ func (a *AppState) validateCircleConfig() error {
 
 	return nil
 }
+
+// validateStateConfig ensures the state persistence backend is configured correctly
+func (a *AppState) validateStateConfig() error {
+	switch a.Config.State.Backend {
+	case "", types.StateBackendMemory:
+	case types.StateBackendBolt:
+		if a.Config.State.Path == "" {
+			return fmt.Errorf("state path must be set in the config when using the %s backend", types.StateBackendBolt)
+		}
+	default:
+		return fmt.Errorf("unknown state backend in the config: %s", a.Config.State.Backend)
+	}
+
+	retention := a.Config.State.Retention
+	if retention.TTL < 0 || retention.MaxEntries < 0 || retention.SweepInterval < 0 {
+		return fmt.Errorf("state retention ttl, max-entries and sweep-interval cannot be negative in the config")
+	}
+
+	return nil
+}
+
+// validateAPIConfig ensures the api is configured correctly
+func (a *AppState) validateAPIConfig() error {
+	api := a.Config.API
+	if (api.TLSCertFile == "") != (api.TLSKeyFile == "") {
+		return fmt.Errorf("api tls-cert-file and tls-key-file must be set together in the config")
+	}
+	return nil
+}
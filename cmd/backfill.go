@@ -0,0 +1,249 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	flagChain = "chain"
+	flagFrom  = "from"
+	flagTo    = "to"
+)
+
+// backfillSummary counts the messages seen by a backfill
+type backfillSummary struct {
+	Found         int `json:"found"`
+	Filtered      int `json:"filtered"`
+	AlreadyMinted int `json:"already_minted"`
+	Relayed       int `json:"relayed"`
+	Failed        int `json:"failed"`
+}
+
+// Command for relaying every missed CCTP message in a block range of a source chain
+func backfillCmd(a *AppState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Scan a block range of a source chain and relay every message that was not minted yet",
+		Long: strings.TrimSpace(`
+Scan a block range of a source chain and relay every CCTP message that was not minted yet.
+Unlike the flush mechanism, any range can be scanned and the relayer does not need to be running.
+Messages on disabled routes, below the min-mint-amount or with another destination caller are filtered.
+Messages whose nonce was already used on the destination chain are skipped.`),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s backfill --chain ethereum --from 19000000 --to 19001000
+$ %s backfill --chain noble --from 5000000 --to 5000500 --json`, appName, appName)),
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			a.InitAppState()
+		},
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := a.Logger
+			cfg := a.Config
+			ctx := cmd.Context()
+
+			jsn, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+			name, err := cmd.Flags().GetString(flagChain)
+			if err != nil {
+				return err
+			}
+			from, err := cmd.Flags().GetUint64(flagFrom)
+			if err != nil {
+				return err
+			}
+			to, err := cmd.Flags().GetUint64(flagTo)
+			if err != nil {
+				return err
+			}
+			if from > to {
+				return fmt.Errorf("--%s %d is greater than --%s %d", flagFrom, from, flagTo, to)
+			}
+
+			chainCfg, ok := cfg.Chains[name]
+			if !ok {
+				return fmt.Errorf("chain %q not found in config", name)
+			}
+			// the source chain is only scanned, so its minter private key is not needed
+			source, err := chainCfg.QueryChain(name)
+			if err != nil {
+				return fmt.Errorf("error creating chain error=%w", err)
+			}
+			if err := source.InitializeClients(ctx, logger); err != nil {
+				return fmt.Errorf("error initializing client error=%w", err)
+			}
+			defer source.CloseClients()
+
+			summary, err := backfill(ctx, cfg, logger, source, from, to)
+			if err != nil {
+				return err
+			}
+
+			if jsn {
+				out, err := json.Marshal(summary)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintf(w, "found:\t%d\n", summary.Found)
+			fmt.Fprintf(w, "filtered:\t%d\n", summary.Filtered)
+			fmt.Fprintf(w, "already minted:\t%d\n", summary.AlreadyMinted)
+			fmt.Fprintf(w, "relayed:\t%d\n", summary.Relayed)
+			fmt.Fprintf(w, "failed:\t%d\n", summary.Failed)
+			return w.Flush()
+		},
+	}
+
+	cmd.Flags().String(flagChain, "", "name of the source chain in the config")
+	cmd.Flags().Uint64(flagFrom, 0, "first block of the range")
+	cmd.Flags().Uint64(flagTo, 0, "last block of the range")
+	_ = cmd.MarkFlagRequired(flagChain)
+	_ = cmd.MarkFlagRequired(flagFrom)
+	_ = cmd.MarkFlagRequired(flagTo)
+
+	return addJSONFlag(cmd)
+}
+
+// backfill scans the source chain from start to end and relays the messages that were not minted yet.
+// Messages are relayed one by one as they are found, so a failure does not stop the backfill.
+func backfill(
+	ctx context.Context,
+	cfg *types.Config,
+	logger log.Logger,
+	source types.Chain,
+	start, end uint64,
+) (backfillSummary, error) {
+	var summary backfillSummary
+
+	processingQueue := make(chan *types.TxState, 100)
+	scanErr := make(chan error, 1)
+	scanCtx, cancel := context.WithCancel(ctx)
+	go func() {
+		scanErr <- source.ConsumeHistory(scanCtx, logger, processingQueue, start, end)
+		close(processingQueue)
+	}()
+	defer func() {
+		// on an early return, the scan is stopped and its remaining txs are drained, so it is not blocked sending them
+		cancel()
+		go func() {
+			for range processingQueue {
+			}
+		}()
+	}()
+
+	destinations := make(map[types.Domain]types.Chain)
+	sequenceMap := types.NewSequenceMap()
+	seen := make(map[string]struct{})
+
+	for tx := range processingQueue {
+		for _, msg := range tx.Msgs {
+			// a tx can be returned more than once if its block was retried
+			key := fmt.Sprintf("%d-%d", msg.SourceDomain, msg.Nonce)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			summary.Found++
+
+			if FilterDisabledCCTPRoutes(cfg, logger, msg) || filterLowTransfers(cfg, logger, msg) {
+				summary.Filtered++
+				continue
+			}
+
+			dest, ok := destinations[msg.DestDomain]
+			if !ok {
+				var err error
+				dest, err = initBackfillDestination(ctx, cfg, logger, msg.DestDomain, sequenceMap)
+				if err != nil {
+					return summary, err
+				}
+				defer dest.CloseClients()
+				destinations[msg.DestDomain] = dest
+			}
+
+			if filterInvalidDestinationCallers(destinations, logger, msg) {
+				summary.Filtered++
+				continue
+			}
+
+			used, err := dest.NonceUsed(ctx, msg.SourceDomain, msg.Nonce)
+			if err != nil {
+				logger.Error("Unable to query used nonce", "nonce", msg.Nonce, "source_domain", msg.SourceDomain, "err", err)
+				summary.Failed++
+				continue
+			}
+			if used {
+				summary.AlreadyMinted++
+				continue
+			}
+
+			if err := pollAttestation(cfg, logger, msg); err != nil {
+				logger.Error("Unable to get attestation", "nonce", msg.Nonce, "source_domain", msg.SourceDomain, "err", err)
+				summary.Failed++
+				continue
+			}
+
+			if err := dest.Broadcast(ctx, logger, []*types.MessageState{msg}, sequenceMap, nil); err != nil {
+				logger.Error("Unable to broadcast", "nonce", msg.Nonce, "source_domain", msg.SourceDomain, "dest", dest.Name(), "err", err)
+				summary.Failed++
+				continue
+			}
+
+			switch {
+			case msg.DestTxHash != "":
+				logger.Info(fmt.Sprintf("Relayed nonce %d from %d to %d in tx %s", msg.Nonce, msg.SourceDomain, msg.DestDomain, msg.DestTxHash))
+				summary.Relayed++
+			case msg.Status == types.Complete:
+				// minted by another relayer since the nonce was checked
+				summary.AlreadyMinted++
+			default:
+				summary.Failed++
+			}
+		}
+
+		logger.Info(fmt.Sprintf("Backfill progress: found %d, filtered %d, already minted %d, relayed %d, failed %d",
+			summary.Found, summary.Filtered, summary.AlreadyMinted, summary.Relayed, summary.Failed))
+	}
+
+	if err := <-scanErr; err != nil {
+		return summary, fmt.Errorf("unable to scan blocks %d to %d error=%w", start, end, err)
+	}
+	return summary, nil
+}
+
+// initBackfillDestination creates the destination chain of the domain and initializes its clients and broadcaster.
+func initBackfillDestination(
+	ctx context.Context,
+	cfg *types.Config,
+	logger log.Logger,
+	domain types.Domain,
+	sequenceMap *types.SequenceMap,
+) (types.Chain, error) {
+	dest, err := chainByDomain(cfg, domain)
+	if err != nil {
+		return nil, err
+	}
+	if err := dest.InitializeClients(ctx, logger); err != nil {
+		return nil, fmt.Errorf("error initializing client error=%w", err)
+	}
+	if err := dest.InitializeBroadcaster(ctx, logger, sequenceMap); err != nil {
+		dest.CloseClients()
+		return nil, fmt.Errorf("error initializing broadcaster error=%w", err)
+	}
+	return dest, nil
+}
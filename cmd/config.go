@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v2"
 
+	"github.com/strangelove-ventures/noble-cctp-relayer/circle"
 	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum"
 	"github.com/strangelove-ventures/noble-cctp-relayer/noble"
 	"github.com/strangelove-ventures/noble-cctp-relayer/types"
@@ -53,6 +57,138 @@ $ %s sc`, appName, defaultConfigPath, appName)),
 	return addJSONFlag(cmd)
 }
 
+// Command for managing the config file
+func configCmd(a *AppState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the relayer config",
+	}
+	cmd.AddCommand(
+		configValidateCmd(a),
+		configInitCmd(),
+	)
+	return cmd
+}
+
+// Command for checking the config against the live chains and attestation api
+func configValidateCmd(a *AppState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the config against the configured endpoints",
+		Long: strings.TrimSpace(`
+Check the config against the configured endpoints.
+Besides the checks run on start, this ensures every endpoint serves the configured chain id, MessageTransmitter
+addresses have contract code, minter keys decode and their addresses can pay for gas, every domain in the
+enabled routes has a configured chain and the Circle attestation api is reachable.`),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s config validate --config %s
+$ %s config validate --json`, appName, defaultConfigPath, appName)),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsn, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+
+			checks, err := validateConfigLive(cmd.Context(), a)
+			if err != nil {
+				return err
+			}
+
+			if jsn {
+				out, err := json.Marshal(checks)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			} else {
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "CHAIN\tCHECK\tRESULT\tDETAIL")
+				for _, check := range checks {
+					result := "pass"
+					if !check.Passed {
+						result = "FAIL"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", check.Chain, check.Check, result, check.Detail)
+				}
+				if err := w.Flush(); err != nil {
+					return err
+				}
+			}
+
+			failed := 0
+			for _, check := range checks {
+				if !check.Passed {
+					failed++
+				}
+			}
+			if failed > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d of %d config checks failed", failed, len(checks))
+			}
+			return nil
+		},
+	}
+	return addJSONFlag(cmd)
+}
+
+// validateConfigLive parses the config at the AppState ConfigPath and runs the static config validation
+// followed by the live checks of every chain, the enabled routes and the attestation api.
+func validateConfigLive(ctx context.Context, a *AppState) ([]types.ConfigCheck, error) {
+	cfg, err := ParseConfig(a.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	a.Config = cfg
+
+	checks := []types.ConfigCheck{types.NewConfigCheck("", "config", a.ConfigPath, a.validateConfig())}
+
+	names := make([]string, 0, len(cfg.Chains))
+	for name := range cfg.Chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	configured := make(map[types.Domain]bool)
+	for _, name := range names {
+		chainCfg := cfg.Chains[name]
+		if domain, ok := chainConfigDomain(chainCfg); ok {
+			configured[domain] = true
+		}
+
+		chain, err := chainCfg.Chain(name)
+		if err != nil {
+			checks = append(checks, types.NewConfigCheck(name, "minter key", "", err))
+			continue
+		}
+		checks = append(checks, chain.CheckConfig(ctx)...)
+	}
+
+	sources := make([]types.Domain, 0, len(cfg.EnabledRoutes))
+	for source := range cfg.EnabledRoutes {
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i] < sources[j] })
+
+	for _, source := range sources {
+		for _, dest := range cfg.EnabledRoutes[source] {
+			var err error
+			switch {
+			case !configured[source]:
+				err = fmt.Errorf("no chain configured for source domain %d", source)
+			case !configured[dest]:
+				err = fmt.Errorf("no chain configured for dest domain %d", dest)
+			}
+			checks = append(checks, types.NewConfigCheck("", fmt.Sprintf("route %d -> %d", source, dest), "", err))
+		}
+	}
+
+	checks = append(checks, types.NewConfigCheck("circle", "attestation api", cfg.Circle.AttestationBaseURL,
+		circle.CheckReachable(ctx, cfg.Circle.AttestationBaseURL)))
+
+	return checks, nil
+}
+
 // ParseConfig parses the app config file
 func ParseConfig(file string) (*types.Config, error) {
 	data, err := os.ReadFile(file)
@@ -68,8 +204,10 @@ func ParseConfig(file string) (*types.Config, error) {
 	c := types.Config{
 		EnabledRoutes:        cfg.EnabledRoutes,
 		Circle:               cfg.Circle,
+		State:                cfg.State,
 		ProcessorWorkerCount: cfg.ProcessorWorkerCount,
 		API:                  cfg.API,
+		Reconcile:            cfg.Reconcile,
 		Chains:               make(map[string]types.ChainConfig),
 	}
 
@@ -79,15 +217,37 @@ func ParseConfig(file string) (*types.Config, error) {
 			return nil, err
 		}
 
+		// values from a referenced preset are applied first, so that fields set in the config override them
+		var ref struct {
+			Preset string `yaml:"preset"`
+		}
+		if err := yaml.Unmarshal(yamlbz, &ref); err != nil {
+			return nil, err
+		}
+		var preset *types.ChainPreset
+		if ref.Preset != "" {
+			p, err := types.LookupChainPreset(ref.Preset)
+			if err != nil {
+				return nil, fmt.Errorf("chain %s: %w", name, err)
+			}
+			preset = &p
+		}
+
 		switch name {
 		case "noble":
 			var cc noble.ChainConfig
+			if preset != nil {
+				cc.ApplyPreset(*preset)
+			}
 			if err := yaml.Unmarshal(yamlbz, &cc); err != nil {
 				return nil, err
 			}
 			c.Chains[name] = &cc
 		default:
 			var cc ethereum.ChainConfig
+			if preset != nil {
+				cc.ApplyPreset(*preset)
+			}
 			if err := yaml.Unmarshal(yamlbz, &cc); err != nil {
 				return nil, err
 			}
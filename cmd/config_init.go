@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum"
+	"github.com/strangelove-ventures/noble-cctp-relayer/noble"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	flagNetwork = "network"
+	flagChains  = "chains"
+	flagOutput  = "output"
+	flagForce   = "force"
+
+	defaultInitOutput = "./config.yaml"
+)
+
+// Command for generating a config from the built-in network presets
+func configInitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Generate a config from the built-in network presets",
+		Long: strings.TrimSpace(fmt.Sprintf(`
+Generate a config from the built-in network presets.
+Domains, chain ids, MessageTransmitter addresses, the Circle attestation url and lookback periods are filled in
+from the preset of each chain. The rpc and websocket endpoints and the minter private keys must be set before starting.
+
+Networks: %s
+Chains: %s`, strings.Join(types.PresetNetworks(), ", "), strings.Join(types.NetworkPresets[types.NetworkMainnet].ChainNames(), ", "))),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s config init --network mainnet --chains noble,ethereum,arbitrum
+$ %s config init --network testnet --chains noble,base --output ./testnet.yaml`, appName, appName)),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			network, err := cmd.Flags().GetString(flagNetwork)
+			if err != nil {
+				return err
+			}
+			chains, err := cmd.Flags().GetStringSlice(flagChains)
+			if err != nil {
+				return err
+			}
+			output, err := cmd.Flags().GetString(flagOutput)
+			if err != nil {
+				return err
+			}
+			force, err := cmd.Flags().GetBool(flagForce)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := generateConfig(network, chains)
+			if err != nil {
+				return err
+			}
+
+			out, err := yaml.Marshal(cfg)
+			if err != nil {
+				return err
+			}
+			header := fmt.Sprintf("# Generated by `%s config init` for %s.\n"+
+				"# Set each chain's rpc (and ws) endpoint and minter-private-key (or the <CHAIN>_PRIV_KEY env variable) before starting.\n\n",
+				appName, network)
+
+			flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+			if force {
+				flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+			}
+			file, err := os.OpenFile(output, flags, 0o600)
+			if errors.Is(err, os.ErrExist) {
+				return fmt.Errorf("%s already exists, use --%s to overwrite it", output, flagForce)
+			}
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			if _, err := file.WriteString(header + string(out)); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s config for %s to %s\n", network, strings.Join(chains, ", "), output)
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagNetwork, types.NetworkMainnet, fmt.Sprintf("network preset (%s)", strings.Join(types.PresetNetworks(), ", ")))
+	cmd.Flags().StringSlice(flagChains, nil, "comma separated chains to include, ex: noble,ethereum,arbitrum")
+	cmd.Flags().StringP(flagOutput, "o", defaultInitOutput, "file to write the config to")
+	cmd.Flags().Bool(flagForce, false, "overwrite the output file if it exists")
+	_ = cmd.MarkFlagRequired(flagChains)
+
+	return cmd
+}
+
+// generateConfig builds a complete config for the chains on the network from the built-in presets.
+// If noble is included, routes between noble and every other chain are enabled. Otherwise, every route between the chains is enabled.
+func generateConfig(network string, chains []string) (*types.Config, error) {
+	networkPreset, ok := types.NetworkPresets[network]
+	if !ok {
+		return nil, fmt.Errorf("unknown network %q, expected one of %s", network, strings.Join(types.PresetNetworks(), ", "))
+	}
+	if len(chains) == 0 {
+		return nil, errors.New("at least one chain is required")
+	}
+
+	cfg := &types.Config{
+		Chains:        make(map[string]types.ChainConfig),
+		EnabledRoutes: make(map[types.Domain][]types.Domain),
+		Circle: types.CircleSettings{
+			AttestationBaseURL: networkPreset.AttestationBaseURL,
+			FetchRetries:       30,
+			FetchRetryInterval: 3,
+		},
+		State: types.StateSettings{
+			Backend: types.StateBackendMemory,
+			Retention: types.RetentionSettings{
+				TTL:           168 * time.Hour,
+				MaxEntries:    100000,
+				SweepInterval: 10 * time.Minute,
+			},
+		},
+		ProcessorWorkerCount: 16,
+		API: types.APISettings{
+			Address: types.DefaultAPIAddress,
+			Port:    types.DefaultAPIPort,
+		},
+	}
+
+	var domains []types.Domain
+	for _, name := range chains {
+		name = strings.TrimSpace(name)
+		preset, ok := networkPreset.Chains[name]
+		if !ok {
+			return nil, fmt.Errorf("no %s preset for chain %q, expected one of %s", network, name, strings.Join(networkPreset.ChainNames(), ", "))
+		}
+		if _, ok := cfg.Chains[name]; ok {
+			return nil, fmt.Errorf("chain %q is listed more than once", name)
+		}
+		domains = append(domains, preset.Domain)
+
+		if name == nobleChainName {
+			cc := &noble.ChainConfig{
+				Workers:                8,
+				GasLimit:               200000,
+				BroadcastRetries:       5,
+				BroadcastRetryInterval: 5,
+				BlockQueueChannelSize:  1000000,
+			}
+			cc.ApplyPreset(preset)
+			cfg.Chains[name] = cc
+			continue
+		}
+
+		cc := &ethereum.ChainConfig{
+			BroadcastRetries:       5,
+			BroadcastRetryInterval: 10,
+			MinMintAmount:          10000000,
+		}
+		cc.ApplyPreset(preset)
+		cfg.Chains[name] = cc
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i] < domains[j] })
+
+	_, hasNoble := cfg.Chains[nobleChainName]
+	nobleDomain := networkPreset.Chains[nobleChainName].Domain
+	for _, source := range domains {
+		for _, dest := range domains {
+			if source == dest || (hasNoble && source != nobleDomain && dest != nobleDomain) {
+				continue
+			}
+			cfg.EnabledRoutes[source] = append(cfg.EnabledRoutes[source], dest)
+		}
+	}
+
+	return cfg, nil
+}
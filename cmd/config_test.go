@@ -1,6 +1,14 @@
 package cmd_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -8,6 +16,7 @@ import (
 	"github.com/strangelove-ventures/noble-cctp-relayer/cmd"
 	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum"
 	"github.com/strangelove-ventures/noble-cctp-relayer/noble"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
 )
 
 func TestConfig(t *testing.T) {
@@ -38,3 +47,116 @@ func TestBlockQueueChannelSize(t *testing.T) {
 
 	require.Equal(t, expected, n.BlockQueueChannelSize)
 }
+
+func TestAPIConfig(t *testing.T) {
+	file, err := cmd.ParseConfig("../config/sample-config.yaml")
+	require.NoError(t, err, "Error parsing config")
+
+	require.True(t, file.API.IsEnabled())
+	require.Equal(t, "localhost:8000", file.API.ListenAddr())
+
+	// unset values fall back to the defaults
+	var empty types.APISettings
+	require.True(t, empty.IsEnabled())
+	require.Equal(t, "localhost:8000", empty.ListenAddr())
+
+	disabled := false
+	custom := types.APISettings{Enabled: &disabled, Address: "0.0.0.0", Port: 9090}
+	require.False(t, custom.IsEnabled())
+	require.Equal(t, "0.0.0.0:9090", custom.ListenAddr())
+}
+
+func TestConfigValidate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := fmt.Sprintf(`
+enabled-routes:
+  0: [4]
+circle:
+  attestation-base-url: %s
+  fetch-retry-interval: 10
+`, server.URL)
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+
+	var out bytes.Buffer
+	root := cmd.NewRootCmd()
+	root.SetOut(&out)
+	root.SetErr(io.Discard)
+	root.SetArgs([]string{"config", "validate", "--config", path, "--json"})
+	require.Error(t, root.Execute())
+
+	var checks []types.ConfigCheck
+	require.NoError(t, json.Unmarshal(out.Bytes(), &checks))
+
+	results := make(map[string]types.ConfigCheck)
+	for _, check := range checks {
+		results[check.Check] = check
+	}
+
+	// no chains are configured for the route
+	require.False(t, results["route 0 -> 4"].Passed)
+	require.Contains(t, results["route 0 -> 4"].Detail, "source domain 0")
+	require.True(t, results["attestation api"].Passed)
+}
+
+func TestConfigPresets(t *testing.T) {
+	file, err := cmd.ParseConfig("../config/sample-config.yaml")
+	require.NoError(t, err, "Error parsing config")
+
+	// values come from the testnet presets
+	eth, ok := file.Chains["ethereum"].(*ethereum.ChainConfig)
+	require.True(t, ok)
+	require.Equal(t, int64(11155111), eth.ChainID)
+	require.Equal(t, types.Domain(0), eth.Domain)
+	require.Equal(t, "0x7865fAfC2db2093669d92c0F33AeEF291086BEFD", eth.MessageTransmitter)
+
+	avax, ok := file.Chains["avalanche"].(*ethereum.ChainConfig)
+	require.True(t, ok)
+	require.Equal(t, types.Domain(1), avax.Domain)
+
+	// fields set in the config override the preset
+	n, ok := file.Chains["noble"].(*noble.ChainConfig)
+	require.True(t, ok)
+	require.Equal(t, "grand-1", n.ChainID)
+	require.Equal(t, uint64(5), n.LookbackPeriod)
+}
+
+func TestConfigInit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	root := cmd.NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetArgs([]string{"config", "init", "--network", "mainnet", "--chains", "noble,ethereum,arbitrum", "--output", path})
+	require.NoError(t, root.Execute())
+
+	file, err := cmd.ParseConfig(path)
+	require.NoError(t, err)
+
+	require.Equal(t, "https://iris-api.circle.com/attestations/", file.Circle.AttestationBaseURL)
+
+	arb, ok := file.Chains["arbitrum"].(*ethereum.ChainConfig)
+	require.True(t, ok)
+	require.Equal(t, int64(42161), arb.ChainID)
+	require.Equal(t, types.Domain(3), arb.Domain)
+	require.Equal(t, "0xC30362313FBBA5cf9163F0bb16a0e01f01A896ca", arb.MessageTransmitter)
+
+	n, ok := file.Chains["noble"].(*noble.ChainConfig)
+	require.True(t, ok)
+	require.Equal(t, "noble-1", n.ChainID)
+
+	// routes between noble and every other chain
+	require.Equal(t, []types.Domain{4}, file.EnabledRoutes[0])
+	require.Equal(t, []types.Domain{4}, file.EnabledRoutes[3])
+	require.Equal(t, []types.Domain{0, 3}, file.EnabledRoutes[4])
+
+	// an existing config is not overwritten
+	root = cmd.NewRootCmd()
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	root.SetArgs([]string{"config", "init", "--chains", "noble", "--output", path})
+	require.Error(t, root.Execute())
+}
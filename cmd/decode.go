@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	flagSourceDomain = "source-domain"
+	flagDestDomain   = "dest-domain"
+
+	nobleDomain = types.Domain(4)
+)
+
+// decodedField is a single human readable field of a decoded message
+type decodedField struct {
+	Name  string
+	Value string
+}
+
+// Command for decoding CCTP messages
+func decodeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decode",
+		Short: "Decode hex encoded CCTP messages, burn message bodies and metadata",
+	}
+	cmd.AddCommand(
+		decodeMessageCmd(),
+		decodeBurnCmd(),
+		decodeMetadataCmd(),
+	)
+	return cmd
+}
+
+func decodeMessageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "message [hex]",
+		Short: "Decode the MessageSent bytes of a CCTP message, including its burn message body",
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s decode message 0x000000000000000000000004...`, appName)),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := decodeHexArg(args[0])
+			if err != nil {
+				return err
+			}
+			fields, err := decodeMessage(bz)
+			if err != nil {
+				return err
+			}
+			return printDecoded(cmd, fields)
+		},
+	}
+	return addJSONFlag(cmd)
+}
+
+func decodeBurnCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "burn [hex]",
+		Short: "Decode a burn message body",
+		Long: strings.TrimSpace(`
+Decode a burn message body.
+Addresses are printed as 32 byte hex unless the domains are given, in which case they are printed in the
+format of their chain (0x addresses on EVM chains, bech32 on Noble).`),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s decode burn 0x00000000...
+$ %s decode burn 0x00000000... --source-domain 0 --dest-domain 4`, appName, appName)),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := decodeHexArg(args[0])
+			if err != nil {
+				return err
+			}
+			source, err := optionalDomainFlag(cmd, flagSourceDomain)
+			if err != nil {
+				return err
+			}
+			dest, err := optionalDomainFlag(cmd, flagDestDomain)
+			if err != nil {
+				return err
+			}
+			fields, err := decodeBurnMessage(bz, source, dest, "")
+			if err != nil {
+				return err
+			}
+			return printDecoded(cmd, fields)
+		},
+	}
+	cmd.Flags().Uint32(flagSourceDomain, 0, "domain the tokens were burned on")
+	cmd.Flags().Uint32(flagDestDomain, 0, "domain the tokens are minted on")
+	return addJSONFlag(cmd)
+}
+
+func decodeMetadataCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "metadata [hex]",
+		Short: "Decode a metadata message body",
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s decode metadata 0x00000000...`, appName)),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bz, err := decodeHexArg(args[0])
+			if err != nil {
+				return err
+			}
+			fields, err := decodeMetadataMessage(bz, "")
+			if err != nil {
+				return err
+			}
+			return printDecoded(cmd, fields)
+		},
+	}
+	return addJSONFlag(cmd)
+}
+
+// decodeMessage decodes MessageSent bytes and their message body.
+func decodeMessage(bz []byte) ([]decodedField, error) {
+	msg, err := new(types.Message).Parse(bz)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse message, expected at least 116 bytes, got %d", len(bz))
+	}
+
+	source := types.Domain(msg.SourceDomain)
+	dest := types.Domain(msg.DestinationDomain)
+	fields := []decodedField{
+		{"iris_lookup_id", "0x" + hex.EncodeToString(crypto.Keccak256(bz))},
+		{"version", fmt.Sprint(msg.Version)},
+		{"source_domain", formatDomain(source)},
+		{"dest_domain", formatDomain(dest)},
+		{"nonce", fmt.Sprint(msg.Nonce)},
+		{"sender", formatAddress(msg.Sender, &source)},
+		{"recipient", formatAddress(msg.Recipient, &dest)},
+		{"destination_caller", formatDestinationCaller(msg.DestinationCaller, dest)},
+	}
+
+	// the body is either a burn message or a metadata message
+	if burn, err := decodeBurnMessage(msg.MessageBody, &source, &dest, "burn."); err == nil {
+		return append(fields, burn...), nil
+	}
+	if metadata, err := decodeMetadataMessage(msg.MessageBody, "metadata."); err == nil {
+		return append(fields, metadata...), nil
+	}
+	return append(fields, decodedField{"message_body", "0x" + hex.EncodeToString(msg.MessageBody)}), nil
+}
+
+// decodeBurnMessage decodes a burn message body. The domains are optional.
+func decodeBurnMessage(bz []byte, source *types.Domain, dest *types.Domain, prefix string) ([]decodedField, error) {
+	burn, err := new(types.BurnMessage).Parse(bz)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse burn message, expected 132 bytes, got %d", len(bz))
+	}
+
+	return []decodedField{
+		{prefix + "version", fmt.Sprint(burn.Version)},
+		{prefix + "burn_token", formatAddress(burn.BurnToken, source)},
+		{prefix + "mint_recipient", formatAddress(burn.MintRecipient, dest)},
+		{prefix + "amount", burn.Amount.String()},
+		{prefix + "message_sender", formatAddress(burn.MessageSender, source)},
+	}, nil
+}
+
+// decodeMetadataMessage decodes a metadata message body. The recipient is encoded with the message's bech32 prefix.
+func decodeMetadataMessage(bz []byte, prefix string) ([]decodedField, error) {
+	metadata, err := new(types.MetadataMessage).Parse(bz)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse metadata message, expected at least 112 bytes, got %d", len(bz))
+	}
+
+	recipient := "0x" + hex.EncodeToString(metadata.Recipient)
+	if metadata.Prefix != "" {
+		if addr, err := sdk.Bech32ifyAddressBytes(metadata.Prefix, trimAddress(metadata.Recipient)); err == nil {
+			recipient = addr
+		}
+	}
+
+	return []decodedField{
+		{prefix + "nonce", fmt.Sprint(metadata.Nonce)},
+		{prefix + "sender", "0x" + hex.EncodeToString(metadata.Sender)},
+		{prefix + "channel", fmt.Sprintf("channel-%d", metadata.Channel)},
+		{prefix + "prefix", metadata.Prefix},
+		{prefix + "recipient", recipient},
+		{prefix + "memo", metadata.Memo},
+	}, nil
+}
+
+func formatDomain(domain types.Domain) string {
+	return fmt.Sprintf("%d (%s)", domain, types.DomainName(domain))
+}
+
+// formatAddress prints a 32 byte CCTP address in the format of the domain's chain:
+// bech32 on Noble, a checksummed 0x address on EVM chains and the full 32 byte hex if the domain is not known.
+func formatAddress(bz []byte, domain *types.Domain) string {
+	switch {
+	case domain == nil || types.DomainName(*domain) == "unknown":
+		return "0x" + hex.EncodeToString(bz)
+	case *domain == nobleDomain:
+		addr, err := sdk.Bech32ifyAddressBytes("noble", trimAddress(bz))
+		if err != nil {
+			return "0x" + hex.EncodeToString(bz)
+		}
+		return addr
+	default:
+		return common.BytesToAddress(bz).Hex()
+	}
+}
+
+func formatDestinationCaller(bz []byte, dest types.Domain) string {
+	if bytes.Equal(bz, make([]byte, len(bz))) {
+		return "none (anyone can receive the message)"
+	}
+	return formatAddress(bz, &dest)
+}
+
+// trimAddress strips the left padding of a 20 byte address. 32 byte addresses are returned as is.
+func trimAddress(bz []byte) []byte {
+	if len(bz) == 32 && bytes.Equal(bz[:12], make([]byte, 12)) {
+		return bz[12:]
+	}
+	return bz
+}
+
+// optionalDomainFlag returns the domain of the flag, or nil if the flag was not set.
+func optionalDomainFlag(cmd *cobra.Command, flag string) (*types.Domain, error) {
+	if !cmd.Flags().Changed(flag) {
+		return nil, nil
+	}
+	d, err := cmd.Flags().GetUint32(flag)
+	if err != nil {
+		return nil, err
+	}
+	domain := types.Domain(d)
+	return &domain, nil
+}
+
+func decodeHexArg(arg string) ([]byte, error) {
+	bz, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(arg), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode hex: %w", err)
+	}
+	return bz, nil
+}
+
+func printDecoded(cmd *cobra.Command, fields []decodedField) error {
+	jsn, err := cmd.Flags().GetBool(flagJSON)
+	if err != nil {
+		return err
+	}
+
+	if jsn {
+		obj := make(map[string]string, len(fields))
+		for _, f := range fields {
+			obj[f.Name] = f.Value
+		}
+		out, err := json.Marshal(obj)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(out))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, f := range fields {
+		fmt.Fprintf(w, "%s:\t%s\n", f.Name, f.Value)
+	}
+	return w.Flush()
+}
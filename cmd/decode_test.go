@@ -0,0 +1,60 @@
+package cmd_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/cmd"
+)
+
+func TestDecodeMessage(t *testing.T) {
+	sender := common.HexToAddress("0xBd3fa81B58Ba92a82136038B25aDec7066af3155")
+	mintRecipient := bytes.Repeat([]byte{0x22}, 20)
+
+	// burn message body
+	body := make([]byte, 132)
+	copy(body[4+12:36], common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48").Bytes())
+	copy(body[36+12:68], mintRecipient)
+	new(big.Int).SetUint64(1000000).FillBytes(body[68:100])
+	copy(body[100+12:132], sender.Bytes())
+
+	// message
+	msg := make([]byte, 116)
+	binary.BigEndian.PutUint32(msg[4:8], 0)
+	binary.BigEndian.PutUint32(msg[8:12], 4)
+	binary.BigEndian.PutUint64(msg[12:20], 42)
+	copy(msg[20+12:52], sender.Bytes())
+	msg = append(msg, body...)
+
+	var out bytes.Buffer
+	root := cmd.NewRootCmd()
+	root.SetOut(&out)
+	root.SetArgs([]string{"decode", "message", "0x" + hex.EncodeToString(msg), "--json"})
+	require.NoError(t, root.Execute())
+
+	var fields map[string]string
+	require.NoError(t, json.Unmarshal(out.Bytes(), &fields))
+
+	nobleRecipient, err := sdk.Bech32ifyAddressBytes("noble", mintRecipient)
+	require.NoError(t, err)
+
+	require.Equal(t, "0x"+hex.EncodeToString(crypto.Keccak256(msg)), fields["iris_lookup_id"])
+	require.Equal(t, "0 (ethereum)", fields["source_domain"])
+	require.Equal(t, "4 (noble)", fields["dest_domain"])
+	require.Equal(t, "42", fields["nonce"])
+	require.Equal(t, sender.Hex(), fields["sender"])
+	require.Equal(t, "none (anyone can receive the message)", fields["destination_caller"])
+	require.Equal(t, nobleRecipient, fields["burn.mint_recipient"])
+	require.Equal(t, "1000000", fields["burn.amount"])
+	require.Equal(t, sender.Hex(), fields["burn.message_sender"])
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/cobra"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	flagAPIAddr    = "api-addr"
+	flagAdminToken = "admin-token"
+
+	defaultAPIAddr = "http://localhost:8000"
+)
+
+// deadLetters holds txs that exhausted their retries until an operator replays them
+var deadLetters = types.NewDeadLetterQueue()
+
+var errNotDeadLettered = errors.New("tx is not in the dead letter queue")
+
+// deadLetter moves the tx to the dead letter queue. Message statuses are left untouched so the State
+// still shows how far each message got; requeueInFlight skips dead lettered txs on startup.
+func deadLetter(logger log.Logger, tx *types.TxState, reason string, attempts int, lastErr error) {
+	dl := &types.DeadLetter{
+		TxState:  tx,
+		Reason:   reason,
+		Attempts: attempts,
+		Created:  time.Now(),
+	}
+	if lastErr != nil {
+		dl.LastError = lastErr.Error()
+	}
+	// the tx state is encoded while State.Mu is held, so it is not mutated meanwhile
+	State.Mu.Lock()
+	err := deadLetters.Add(dl)
+	State.Mu.Unlock()
+	if err != nil {
+		logger.Error("Unable to add tx to the dead letter queue", "tx", tx.TxHash, "err", err)
+		return
+	}
+	logger.Error("Moved tx to the dead letter queue", "tx", tx.TxHash, "reason", reason, "attempts", attempts, "last_error", dl.LastError)
+}
+
+// replayDeadLetter removes the tx from the dead letter queue, resets messages a broadcaster gave up on and places
+// the tx back on the processing queue. Messages that were already attested are broadcast again without re-querying Circle.
+func replayDeadLetter(txHash string, processingQueue chan *types.TxState) (*types.TxState, error) {
+	dl, ok := deadLetters.Load(txHash)
+	if !ok {
+		return nil, errNotDeadLettered
+	}
+
+	tx := dl.TxState
+	snapshot := statusSnapshot(tx)
+	State.Mu.Lock()
+	for _, msg := range tx.Msgs {
+		if msg.Status != types.Failed {
+			continue
+		}
+		if msg.Attestation != "" {
+			msg.Status = types.Attested
+		} else {
+			msg.Status = types.Created
+		}
+		msg.Updated = time.Now()
+	}
+	tx.RetryAttempt = 0
+	State.Mu.Unlock()
+	publishTransitions(tx, snapshot)
+
+	if err := State.Store(tx.TxHash, tx); err != nil {
+		return nil, fmt.Errorf("unable to persist tx state: %w", err)
+	}
+	if err := deadLetters.Remove(txHash); err != nil {
+		return nil, fmt.Errorf("unable to remove tx from the dead letter queue: %w", err)
+	}
+
+	processingQueue <- tx
+	return tx, nil
+}
+
+func getDeadLetters(c *gin.Context) {
+	c.JSON(http.StatusOK, deadLetters.List())
+}
+
+// Command for inspecting and replaying the dead letter queue of a running relayer
+func dlqCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dlq",
+		Short: "Inspect and replay txs that exhausted their retries",
+		Long: strings.TrimSpace(`
+Inspect and replay txs that exhausted their retries.
+These commands talk to the API of a running relayer, as the relayer holds the lock on its state database.`),
+	}
+	cmd.PersistentFlags().String(flagAPIAddr, defaultAPIAddr, "address of the running relayer's API")
+	cmd.PersistentFlags().String(flagAdminToken, "", fmt.Sprintf("admin api bearer token (defaults to the %s env variable)", envAdminToken))
+
+	cmd.AddCommand(
+		dlqListCmd(),
+		dlqReplayCmd(),
+	)
+	return cmd
+}
+
+func dlqListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List txs in the dead letter queue",
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s dlq list
+$ %s dlq list --json --api-addr http://localhost:8000`, appName, appName)),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsn, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+
+			body, err := callAPI(cmd, http.MethodGet, "/admin/dlq")
+			if err != nil {
+				return err
+			}
+
+			if jsn {
+				fmt.Fprintln(cmd.OutOrStdout(), string(body))
+				return nil
+			}
+
+			var letters []*types.DeadLetter
+			if err := json.Unmarshal(body, &letters); err != nil {
+				return fmt.Errorf("unable to parse dead letters: %w", err)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TX HASH\tMSGS\tREASON\tATTEMPTS\tCREATED\tLAST ERROR")
+			for _, dl := range letters {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%s\t%s\n",
+					dl.TxState.TxHash, len(dl.TxState.Msgs), dl.Reason, dl.Attempts, dl.Created.Format(time.RFC3339), dl.LastError)
+			}
+			return w.Flush()
+		},
+	}
+	return addJSONFlag(cmd)
+}
+
+func dlqReplayCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "replay [tx-hash]",
+		Short: "Place a tx from the dead letter queue back onto the processing queue",
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s dlq replay 0xabc...`, appName)),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := callAPI(cmd, http.MethodPost, "/admin/dlq/"+url.PathEscape(args[0])+"/replay"); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Replaying %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+// callAPI sends an authenticated request to the running relayer's admin API and returns the response body.
+// Non 2xx responses are returned as errors.
+func callAPI(cmd *cobra.Command, method string, path string) ([]byte, error) {
+	addr, err := cmd.Flags().GetString(flagAPIAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := cmd.Flags().GetString(flagAdminToken)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		token = os.Getenv(envAdminToken)
+	}
+
+	req, err := http.NewRequestWithContext(cmd.Context(), method, strings.TrimSuffix(addr, "/")+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to reach relayer api at %s: %w", addr, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("relayer api returned %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return nil, fmt.Errorf("relayer api returned %d", resp.StatusCode)
+	}
+	return body, nil
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	// statusEventBufferSize is how many status transitions are kept for clients resuming a stream
+	statusEventBufferSize = 10000
+
+	// subscriberBufferSize is how many status transitions can queue up for a slow stream client before it is dropped
+	subscriberBufferSize = 1000
+
+	streamKeepAliveInterval = 15 * time.Second
+)
+
+// statusEvents publishes every MessageState status transition to the /events stream
+var statusEvents = types.NewStatusEvents(statusEventBufferSize)
+
+// statusSnapshot returns the current status of each message in the tx.
+func statusSnapshot(tx *types.TxState) []string {
+	State.Mu.Lock()
+	defer State.Mu.Unlock()
+
+	statuses := make([]string, len(tx.Msgs))
+	for i, msg := range tx.Msgs {
+		statuses[i] = msg.Status
+	}
+	return statuses
+}
+
+// publishTransitions publishes an event for each message whose status differs from the snapshot
+// and returns a new snapshot. A nil snapshot publishes every message as newly seen.
+func publishTransitions(tx *types.TxState, snapshot []string) []string {
+	State.Mu.Lock()
+	defer State.Mu.Unlock()
+
+	statuses := make([]string, len(tx.Msgs))
+	for i, msg := range tx.Msgs {
+		statuses[i] = msg.Status
+
+		var previous string
+		if i < len(snapshot) {
+			previous = snapshot[i]
+		}
+		if previous == msg.Status {
+			continue
+		}
+
+		statusEvents.Publish(types.StatusEvent{
+			TxHash:         tx.TxHash,
+			IrisLookupID:   msg.IrisLookupID,
+			SourceDomain:   msg.SourceDomain,
+			DestDomain:     msg.DestDomain,
+			Nonce:          msg.Nonce,
+			PreviousStatus: previous,
+			Status:         msg.Status,
+			Time:           time.Now(),
+		})
+	}
+	return statuses
+}
+
+// streamEvents streams status transitions as Server-Sent Events.
+//
+// Query params (all optional):
+//   - tx_hash: only transitions of messages from this source tx
+//   - domain: only transitions of messages from or to this domain
+//
+// A reconnecting client resumes after the last event it received by sending its id in the
+// Last-Event-ID header (or the cursor query param). If transitions after the cursor are no longer buffered,
+// a "reset" event is sent first; the client should reload current state through the /messages endpoint.
+func streamEvents(c *gin.Context) {
+	txHash := c.Query("tx_hash")
+
+	var domain *types.Domain
+	if v := c.Query("domain"); v != "" {
+		d, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse domain"})
+			return
+		}
+		dd := types.Domain(d)
+		domain = &dd
+	}
+
+	cursorParam := c.GetHeader("Last-Event-ID")
+	if cursorParam == "" {
+		cursorParam = c.Query("cursor")
+	}
+	var cursor uint64
+	if cursorParam != "" {
+		var err error
+		cursor, err = strconv.ParseUint(cursorParam, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse cursor"})
+			return
+		}
+	}
+
+	matches := func(event types.StatusEvent) bool {
+		if txHash != "" && !strings.EqualFold(event.TxHash, txHash) {
+			return false
+		}
+		if domain != nil && event.SourceDomain != *domain && event.DestDomain != *domain {
+			return false
+		}
+		return true
+	}
+
+	backlog, complete, events, cancel := statusEvents.Subscribe(cursor, subscriberBufferSize)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	if !complete {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, event := range backlog {
+		if matches(event) {
+			if err := writeStatusEvent(w, event); err != nil {
+				return
+			}
+		}
+	}
+	w.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				// too slow to keep up, the client can reconnect from its last event id
+				return
+			}
+			if !matches(event) {
+				continue
+			}
+			if err := writeStatusEvent(w, event); err != nil {
+				return
+			}
+		}
+		w.Flush()
+	}
+}
+
+func writeStatusEvent(w io.Writer, event types.StatusEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: status\ndata: %s\n\n", event.ID, data)
+	return err
+}
@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/relayer"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+// feeHoldCheckInterval is how often the network fee of destinations with held txs is checked
+const feeHoldCheckInterval = 30 * time.Second
+
+// feeHold is a tx waiting for the network fee of one or more of its destinations to drop below their cap
+type feeHold struct {
+	tx    *types.TxState
+	dests map[types.Domain]types.Chain
+
+	// parked is set once the tx only waits for the fee to drop. Until then the processor is still retrying its other
+	// messages, and only skips the held destinations.
+	parked bool
+}
+
+// feeHeldTxs maps source tx hash -> the tx's hold. A tx is held once, however many of its destinations are above their cap.
+var feeHeldTxs = struct {
+	sync.Mutex
+	holds map[string]*feeHold
+}{holds: make(map[string]*feeHold)}
+
+// holdForFees holds the messages of a tx to the destinations, which were not broadcast because the network fee is
+// above their cap. A parked tx is not retried until the fee of one of them drops. A tx that is not parked is still
+// retried for its other messages, without broadcasting to the held destinations.
+func holdForFees(logger log.Logger, tx *types.TxState, dests []types.Chain, parked bool, metrics *relayer.PromMetrics) {
+	feeHeldTxs.Lock()
+	defer feeHeldTxs.Unlock()
+	hold := &feeHold{tx: tx, dests: make(map[types.Domain]types.Chain), parked: parked}
+	for _, dest := range dests {
+		hold.dests[dest.Domain()] = dest
+	}
+	previous := feeHeldTxs.holds[tx.TxHash]
+	feeHeldTxs.holds[tx.TxHash] = hold
+
+	for _, dest := range dests {
+		setFeeHeldMetric(dest, metrics)
+		if previous == nil || previous.dests[dest.Domain()] == nil {
+			logger.Info(fmt.Sprintf("Holding messages until the network fee on %s drops below its cap", dest.Name()), "tx", tx.TxHash)
+		}
+	}
+	if previous != nil {
+		for domain, dest := range previous.dests {
+			if _, ok := hold.dests[domain]; !ok {
+				setFeeHeldMetric(dest, metrics)
+			}
+		}
+	}
+}
+
+// feeHeldFor reports whether the messages of the tx to the domain are held for fees.
+func feeHeldFor(txHash string, domain types.Domain) bool {
+	feeHeldTxs.Lock()
+	defer feeHeldTxs.Unlock()
+	hold, ok := feeHeldTxs.holds[txHash]
+	if !ok {
+		return false
+	}
+	_, ok = hold.dests[domain]
+	return ok
+}
+
+// setFeeHeldMetric sets the number of attested messages held for the destination. Callers hold the feeHeldTxs lock.
+func setFeeHeldMetric(dest types.Chain, metrics *relayer.PromMetrics) {
+	if metrics == nil {
+		return
+	}
+	count := 0
+	for _, hold := range feeHeldTxs.holds {
+		if _, ok := hold.dests[dest.Domain()]; !ok {
+			continue
+		}
+		for _, msg := range hold.tx.Msgs {
+			if msg.DestDomain == dest.Domain() && msg.Status == types.Attested {
+				count++
+			}
+		}
+	}
+	metrics.SetFeeHeld(dest.Name(), fmt.Sprint(dest.Domain()), count)
+}
+
+// startFeeHoldReleaser checks the network fee of destinations with held txs every feeHoldCheckInterval, and places
+// parked txs back on the processing queue once the fee of one of their destinations is below the cap. Messages to
+// destinations still above their cap are held again by the processor. Txs that are not parked are already being
+// retried, and are broadcast to the destination on their next attempt.
+func startFeeHoldReleaser(
+	ctx context.Context,
+	logger log.Logger,
+	metrics *relayer.PromMetrics,
+	processingQueue chan *types.TxState,
+) {
+	logger = logger.With("routine", "fee-hold")
+
+	ticker := time.NewTicker(feeHoldCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		feeHeldTxs.Lock()
+		dests := make(map[types.Domain]types.Chain)
+		for _, hold := range feeHeldTxs.holds {
+			for domain, dest := range hold.dests {
+				dests[domain] = dest
+			}
+		}
+		feeHeldTxs.Unlock()
+
+		cleared := make(map[types.Domain]bool)
+		for domain, dest := range dests {
+			if err := dest.CheckFees(ctx); err != nil {
+				logger.Debug(fmt.Sprintf("Still holding txs for %s", dest.Name()), "err", err)
+				continue
+			}
+			logger.Info(fmt.Sprintf("Network fee on %s is below its cap, releasing its held txs", dest.Name()))
+			cleared[domain] = true
+		}
+		if len(cleared) == 0 {
+			continue
+		}
+
+		var release []*types.TxState
+		feeHeldTxs.Lock()
+		for txHash, hold := range feeHeldTxs.holds {
+			for domain := range hold.dests {
+				if !cleared[domain] {
+					continue
+				}
+				if hold.parked {
+					release = append(release, hold.tx)
+					delete(feeHeldTxs.holds, txHash)
+					break
+				}
+				delete(hold.dests, domain)
+			}
+			if len(hold.dests) == 0 {
+				delete(feeHeldTxs.holds, txHash)
+			}
+		}
+		for _, dest := range dests {
+			setFeeHeldMetric(dest, metrics)
+		}
+		feeHeldTxs.Unlock()
+
+		for _, tx := range release {
+			processingQueue <- tx
+		}
+	}
+}
@@ -14,6 +14,11 @@ const (
 	flagMetricsPort   = "metrics-port"
 	flagFlushInterval = "flush-interval"
 	flagFlushOnlyMode = "flush-only-mode"
+
+	flagIgnoreCheckpoints = "ignore-checkpoints"
+	flagDomain            = "domain"
+	flagTx                = "tx"
+	flagDryRun            = "dry-run"
 )
 
 func addAppPersistantFlags(cmd *cobra.Command, a *AppState) *cobra.Command {
@@ -26,6 +31,11 @@ func addAppPersistantFlags(cmd *cobra.Command, a *AppState) *cobra.Command {
 	return cmd
 }
 
+func addDryRunFlag(cmd *cobra.Command) *cobra.Command {
+	cmd.Flags().Bool(flagDryRun, false, "simulate mints on the destination chains instead of broadcasting them")
+	return cmd
+}
+
 func addJSONFlag(cmd *cobra.Command) *cobra.Command {
 	cmd.Flags().Bool(flagJSON, false, "return in json format")
 	return cmd
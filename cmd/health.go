@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/circle"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+// readinessCheckTimeout bounds how long the readiness probe waits on each component
+const readinessCheckTimeout = 5 * time.Second
+
+const circleComponent = "circle"
+
+type healthResponse struct {
+	Status     string            `json:"status"`
+	Version    versionInfo       `json:"version"`
+	Components map[string]string `json:"components,omitempty"`
+	Failing    []string          `json:"failing,omitempty"`
+}
+
+// getHealthz reports that the API, and therefore the relayer process, is up.
+func getHealthz(c *gin.Context) {
+	c.JSON(http.StatusOK, healthResponse{Status: "ok", Version: getVersionInfo()})
+}
+
+// getReadyz reports whether every registered chain and the Circle attestation api are ready.
+// Components that are not ready are listed in "failing" along with their error in "components".
+func getReadyz(registeredDomains map[types.Domain]types.Chain) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res := healthResponse{
+			Status:     "ready",
+			Version:    getVersionInfo(),
+			Components: make(map[string]string),
+		}
+
+		check := func(component string, err error) {
+			if err != nil {
+				res.Components[component] = err.Error()
+				res.Failing = append(res.Failing, component)
+				return
+			}
+			res.Components[component] = "ok"
+		}
+
+		for _, chain := range registeredDomains {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), readinessCheckTimeout)
+			check(chain.Name(), chain.CheckHealth(ctx))
+			cancel()
+		}
+		check(circleComponent, circle.CheckHealth())
+		sort.Strings(res.Failing)
+
+		if len(res.Failing) > 0 {
+			res.Status = "not ready"
+			c.JSON(http.StatusServiceUnavailable, res)
+			return
+		}
+		c.JSON(http.StatusOK, res)
+	}
+}
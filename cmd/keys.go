@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum"
+	"github.com/strangelove-ventures/noble-cctp-relayer/noble"
+)
+
+const (
+	flagKeyType = "type"
+
+	keyTypeEVM   = "evm"
+	keyTypeNoble = "noble"
+)
+
+// minterKey is a chain's minter address as printed by keys show
+type minterKey struct {
+	Chain   string `json:"chain"`
+	Address string `json:"address,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// generatedKey is a new private key as printed by keys generate
+type generatedKey struct {
+	Type       string `json:"type"`
+	Address    string `json:"address"`
+	PrivateKey string `json:"private_key"`
+}
+
+// Command for managing minter keys
+func keysCmd(a *AppState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Show and generate minter keys",
+	}
+	cmd.AddCommand(
+		keysShowCmd(a),
+		keysGenerateCmd(),
+	)
+	return cmd
+}
+
+// Command for printing the minter address of each configured chain
+func keysShowCmd(a *AppState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the minter address of each configured chain",
+		Long: strings.TrimSpace(`
+Print the minter address of each configured chain, so it can be funded before the relayer is started.
+Keys are resolved like the start command does: the <CHAIN>_PRIV_KEY env variable takes precedence over the
+minter-private-key in the config. Private keys are never printed.`),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s keys show --config %s
+$ %s keys show --json`, appName, defaultConfigPath, appName)),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsn, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+
+			// the config does not need to be valid yet to print its keys
+			cfg, err := ParseConfig(a.ConfigPath)
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(cfg.Chains))
+			for name := range cfg.Chains {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			keys := make([]minterKey, 0, len(names))
+			for _, name := range names {
+				key := minterKey{Chain: name}
+				key.Address, key.Source, err = cfg.Chains[name].MinterAddress(name)
+				if err != nil {
+					key.Error = err.Error()
+				}
+				keys = append(keys, key)
+			}
+
+			if jsn {
+				out, err := json.Marshal(keys)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "CHAIN\tADDRESS\tSOURCE\tERROR")
+			for _, key := range keys {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key.Chain, key.Address, key.Source, key.Error)
+			}
+			return w.Flush()
+		},
+	}
+	return addJSONFlag(cmd)
+}
+
+// Command for generating new minter keys
+func keysGenerateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate new minter private keys",
+		Long: strings.TrimSpace(fmt.Sprintf(`
+Generate new minter private keys in the hex format expected by minter-private-key and the <CHAIN>_PRIV_KEY env variables.
+An %s key is used by every EVM chain, a %s key by Noble. Both are generated unless --%s is set.
+The private keys are printed, store them securely.`, keyTypeEVM, keyTypeNoble, flagKeyType)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s keys generate
+$ %s keys generate --type noble --json`, appName, appName)),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			jsn, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+			keyType, err := cmd.Flags().GetString(flagKeyType)
+			if err != nil {
+				return err
+			}
+
+			var keyTypes []string
+			switch keyType {
+			case "":
+				keyTypes = []string{keyTypeEVM, keyTypeNoble}
+			case keyTypeEVM, keyTypeNoble:
+				keyTypes = []string{keyType}
+			default:
+				return fmt.Errorf("unknown key type %q, expected %s or %s", keyType, keyTypeEVM, keyTypeNoble)
+			}
+
+			keys := make([]generatedKey, 0, len(keyTypes))
+			for _, t := range keyTypes {
+				key := generatedKey{Type: t}
+				switch t {
+				case keyTypeEVM:
+					key.PrivateKey, key.Address, err = ethereum.GenerateKey()
+				case keyTypeNoble:
+					key.PrivateKey, key.Address, err = noble.GenerateKey()
+				}
+				if err != nil {
+					return err
+				}
+				keys = append(keys, key)
+			}
+
+			if jsn {
+				out, err := json.Marshal(keys)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(out))
+				return nil
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TYPE\tADDRESS\tPRIVATE KEY")
+			for _, key := range keys {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", key.Type, key.Address, key.PrivateKey)
+			}
+			return w.Flush()
+		},
+	}
+	cmd.Flags().String(flagKeyType, "", fmt.Sprintf("key type to generate (%s, %s)", keyTypeEVM, keyTypeNoble))
+	return addJSONFlag(cmd)
+}
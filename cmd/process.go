@@ -2,14 +2,12 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
-	"os"
-	"strconv"
+	"net"
 	"time"
 
 	cctptypes "github.com/circlefin/noble-cctp/x/cctp/types"
-	"github.com/gin-gonic/gin"
 	"github.com/spf13/cobra"
 
 	"cosmossdk.io/log"
@@ -30,6 +28,9 @@ var State = types.NewStateMap()
 // SequenceMap maps the domain -> the equivalent minter account sequence or nonce
 var sequenceMap = types.NewSequenceMap()
 
+// checkpoints holds the last fully processed block height of each chain's listener
+var checkpoints = types.NewCheckpoints()
+
 func Start(a *AppState) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "start",
@@ -60,8 +61,61 @@ func Start(a *AppState) *cobra.Command {
 				}
 			}
 
-			// start API on normal relayer only
-			go startAPI(a)
+			ignoreCheckpoints, err := cmd.Flags().GetBool(flagIgnoreCheckpoints)
+			if err != nil {
+				return fmt.Errorf("invalid ignore checkpoints flag error=%w", err)
+			}
+
+			a.DryRun, err = cmd.Flags().GetBool(flagDryRun)
+			if err != nil {
+				return fmt.Errorf("invalid dry run flag error=%w", err)
+			}
+			if a.DryRun {
+				logger.Info("Dry run: mints will be simulated on the destination chains instead of broadcast")
+			}
+
+			// a dry run must not change what a later run resumes from, so nothing it records is persisted
+			stateCfg := cfg.State
+			if a.DryRun {
+				stateCfg.Backend = types.StateBackendMemory
+				stateCfg.Retention.ArchiveFile = ""
+			}
+			if err := initState(stateCfg, logger); err != nil {
+				return fmt.Errorf("unable to initialize state error=%w", err)
+			}
+			defer func() {
+				if err := checkpoints.Flush(); err != nil {
+					logger.Error("Error persisting block checkpoints", "error", err)
+				}
+				if err := State.Close(); err != nil {
+					logger.Error("Error closing state", "error", err)
+				}
+				if err := closeArchive(); err != nil {
+					logger.Error("Error closing archive", "error", err)
+				}
+			}()
+
+			// bind the api address early so that an unavailable address fails before any chain is started
+			var apiListener net.Listener
+			if cfg.API.IsEnabled() {
+				apiListener, err = listenAPI(cfg.API)
+				if err != nil {
+					return fmt.Errorf("unable to start api error=%w", err)
+				}
+				defer apiListener.Close()
+			} else {
+				logger.Info("API disabled in the config")
+			}
+
+			if err := startRetention(cmd.Context(), logger, stateCfg.Retention); err != nil {
+				return fmt.Errorf("unable to start state retention error=%w", err)
+			}
+
+			if ignoreCheckpoints {
+				logger.Info("Ignoring saved block checkpoints")
+				checkpoints.Clear()
+			}
+			go flushCheckpoints(cmd.Context(), logger)
 
 			// messageState processing queue
 			var processingQueue = make(chan *types.TxState, 10000)
@@ -106,7 +160,7 @@ func Start(a *AppState) *cobra.Command {
 					return fmt.Errorf("error initializing broadcaster error=%w", err)
 				}
 
-				go c.StartListener(cmd.Context(), logger, processingQueue, flushOnly, flushInterval)
+				go c.StartListener(cmd.Context(), logger, processingQueue, checkpoints, flushOnly, flushInterval)
 
 				go c.WalletBalanceMetric(cmd.Context(), a.Logger, metrics)
 
@@ -117,28 +171,53 @@ func Start(a *AppState) *cobra.Command {
 				registeredDomains[c.Domain()] = c
 			}
 
+			// serve API once every chain is registered
+			apiErr := make(chan error, 1)
+			if apiListener != nil {
+				go func() {
+					apiErr <- serveAPI(cmd.Context(), a, apiListener, processingQueue, registeredDomains)
+				}()
+			}
+
 			// spin up Processor worker pool
 			for i := 0; i < int(cfg.ProcessorWorkerCount); i++ {
 				go StartProcessor(cmd.Context(), a, registeredDomains, processingQueue, sequenceMap, metrics)
 			}
 
-			// wait for context to be done
-			<-cmd.Context().Done()
+			// resume transfers that were in flight before the last shutdown
+			requeueInFlight(logger, processingQueue)
+
+			go startReconciler(cmd.Context(), a, registeredDomains, processingQueue, metrics)
+
+			go startFeeHoldReleaser(cmd.Context(), logger, metrics, processingQueue)
+
+			// wait for context to be done, or for the api server to fail
+			var runErr error
+			select {
+			case <-cmd.Context().Done():
+			case err := <-apiErr:
+				if err != nil {
+					runErr = fmt.Errorf("api server error=%w", err)
+				}
+			}
 
 			// close clients & output latest block heights
 			for _, c := range registeredDomains {
-				logger.Info(fmt.Sprintf("%s: latest-block: %d last-flushed-block: %d", c.Name(), c.LatestBlock(), c.LastFlushedBlock()))
+				checkpoint, _ := checkpoints.Load(c.Name())
+				logger.Info(fmt.Sprintf("%s: latest-block: %d last-flushed-block: %d checkpoint: %d", c.Name(), c.LatestBlock(), c.LastFlushedBlock(), checkpoint))
 				err := c.CloseClients()
 				if err != nil {
 					logger.Error("Error closing clients", "error", err)
 				}
 			}
 
-			return nil
+			return runErr
 		},
 	}
 
-	return cmd
+	cmd.Flags().Bool(flagIgnoreCheckpoints, false, "ignore saved block checkpoints and start each chain from its configured start-block (or the latest block)")
+
+	return addDryRunFlag(cmd)
 }
 
 // StartProcessor is the main processing pipeline.
@@ -159,15 +238,28 @@ func StartProcessor(
 		// if this is the first time seeing this message, add it to the State
 		tx, ok := State.Load(dequeuedTx.TxHash)
 		if !ok {
-			State.Store(dequeuedTx.TxHash, dequeuedTx)
-			tx, _ = State.Load(dequeuedTx.TxHash)
-			for _, msg := range tx.Msgs {
+			for _, msg := range dequeuedTx.Msgs {
 				msg.Status = types.Created
 			}
+			if err := State.Store(dequeuedTx.TxHash, dequeuedTx); err != nil {
+				logger.Error("Unable to persist tx state", "tx", dequeuedTx.TxHash, "err", err)
+			} else {
+				// the listener's checkpoint may move past the tx once it is persisted
+				checkpoints.Release(dequeuedTx.TxHash)
+			}
+			publishTransitions(dequeuedTx, nil)
+			tx, _ = State.Load(dequeuedTx.TxHash)
+		} else {
+			checkpoints.Release(tx.TxHash)
 		}
 
+		// status transitions are published to the /events stream after each step
+		snapshot := statusSnapshot(tx)
+
 		var broadcastMsgs = make(map[types.Domain][]*types.MessageState)
-		var requeue bool
+		var requeue, held, pending bool
+		var feeHeld []types.Chain
+		var lastErr error
 		for _, msg := range tx.Msgs {
 			// if a filter's condition is met, mark as filtered
 			if FilterDisabledCCTPRoutes(cfg, logger, msg) ||
@@ -178,6 +270,19 @@ func StartProcessor(
 				State.Mu.Unlock()
 			}
 
+			// messages on routes paused by an operator are held until the route is resumed
+			if (msg.Status == types.Created || msg.Status == types.Pending || msg.Status == types.Attested) &&
+				pausedRoutes.IsPaused(types.Route{SourceDomain: msg.SourceDomain, DestDomain: msg.DestDomain}) {
+				held = true
+				continue
+			}
+
+			// messages attested to in a previous attempt (or before a restart) are ready to broadcast
+			if msg.Status == types.Attested {
+				broadcastMsgs[msg.DestDomain] = append(broadcastMsgs[msg.DestDomain], msg)
+				continue
+			}
+
 			// if the message is burned or pending, check for an attestation
 			if msg.Status == types.Created || msg.Status == types.Pending {
 				response := circle.CheckAttestation(cfg.Circle.AttestationBaseURL, logger, msg.IrisLookupID, msg.SourceTxHash, msg.SourceDomain, msg.DestDomain)
@@ -185,6 +290,7 @@ func StartProcessor(
 				switch {
 				case response == nil:
 					logger.Debug("Attestation is still processing for 0x" + msg.IrisLookupID + ".  Retrying...")
+					lastErr = fmt.Errorf("attestation not found for 0x%s", msg.IrisLookupID)
 					requeue = true
 					continue
 				case msg.Status == types.Created && response.Status == "pending_confirmations":
@@ -193,10 +299,12 @@ func StartProcessor(
 					msg.Status = types.Pending
 					msg.Updated = time.Now()
 					State.Mu.Unlock()
+					lastErr = fmt.Errorf("attestation pending confirmations for 0x%s", msg.IrisLookupID)
 					requeue = true
 					continue
 				case response.Status == "pending_confirmations":
 					logger.Debug("Attestation is still pending for 0x" + msg.IrisLookupID + ".  Retrying...")
+					lastErr = fmt.Errorf("attestation pending confirmations for 0x%s", msg.IrisLookupID)
 					requeue = true
 					continue
 				case response.Status == "complete":
@@ -213,6 +321,8 @@ func StartProcessor(
 			}
 		}
 
+		snapshot = publishTransitions(tx, snapshot)
+
 		// if the message is attested to, try to broadcast
 		for domain, msgs := range broadcastMsgs {
 			chain, ok := registeredDomains[domain]
@@ -221,9 +331,37 @@ func StartProcessor(
 				continue
 			}
 
-			if err := chain.Broadcast(ctx, logger, msgs, sequenceMap, metrics); err != nil {
-				logger.Error("Unable to mint one or more transfers", "error(s)", err, "total_transfers", len(msgs), "name", chain.Name(), "domain", domain)
-				requeue = true
+			// messages held for fees are not broadcast again while the tx is retried for its other messages
+			if feeHeldFor(tx.TxHash, domain) {
+				feeHeld = append(feeHeld, chain)
+				continue
+			}
+
+			var err error
+			if a.DryRun {
+				err = chain.Simulate(ctx, logger, msgs)
+			} else {
+				err = chain.Broadcast(ctx, logger, msgs, sequenceMap, metrics)
+			}
+			if err != nil {
+				switch {
+				case errors.Is(err, types.ErrFeeCapExceeded):
+					// messages are held, not retried, while the network fee is above the cap
+					feeHeld = append(feeHeld, chain)
+				case errors.Is(err, types.ErrTxPending):
+					// the mint txs may still be mined, they are waited on again without counting a retry
+					logger.Info("Mint txs not confirmed yet, waiting on them again", "err", err, "name", chain.Name(), "domain", domain)
+					pending = true
+				default:
+					logger.Error("Unable to mint one or more transfers", "error(s)", err, "total_transfers", len(msgs), "name", chain.Name(), "domain", domain)
+					lastErr = err
+					requeue = true
+				}
+				continue
+			}
+
+			// simulations record their own result on each message
+			if a.DryRun {
 				continue
 			}
 
@@ -235,15 +373,59 @@ func StartProcessor(
 			State.Mu.Unlock()
 		}
 
-		// requeue txs, ensure not to exceed retry limit
-		if requeue {
-			if dequeuedTx.RetryAttempt < cfg.Circle.FetchRetries {
-				dequeuedTx.RetryAttempt++
-				time.Sleep(time.Duration(cfg.Circle.FetchRetryInterval) * time.Second)
-				processingQueue <- tx
-			} else {
-				logger.Error("Retry limit exceeded for tx", "limit", cfg.Circle.FetchRetries, "tx", dequeuedTx.TxHash)
+		publishTransitions(tx, snapshot)
+
+		// persist status changes made during this attempt
+		if err := State.Store(tx.TxHash, tx); err != nil {
+			logger.Error("Unable to persist tx state", "tx", tx.TxHash, "err", err)
+		} else {
+			checkpoints.Release(tx.TxHash)
+		}
+
+		// broadcasters mark messages as failed once they have given up on them
+		var broadcastFailed bool
+		for _, msg := range tx.Msgs {
+			if msg.Status == types.Failed {
+				broadcastFailed = true
+			}
+		}
+
+		// requeue txs, ensure not to exceed retry limit. Txs with failed messages are only dead lettered once their
+		// other messages are no longer in progress, so that those are not held back until the tx is replayed.
+		// Messages on paused routes or held for fees are skipped while the tx is retried, and a tx with held messages
+		// is held rather than dead lettered once it runs out of retries.
+		retryLimit := requeue && dequeuedTx.RetryAttempt >= cfg.Circle.FetchRetries
+		if retryLimit {
+			logger.Error("Retry limit exceeded for tx", "limit", cfg.Circle.FetchRetries, "tx", dequeuedTx.TxHash)
+		}
+		switch {
+		case requeue && !retryLimit:
+			if len(feeHeld) > 0 {
+				holdForFees(logger, tx, feeHeld, false, metrics)
+			}
+			State.Mu.Lock()
+			dequeuedTx.RetryAttempt++
+			State.Mu.Unlock()
+			time.Sleep(time.Duration(cfg.Circle.FetchRetryInterval) * time.Second)
+			processingQueue <- tx
+		case pending:
+			// messages of the tx are still being minted, so it is neither held nor dead lettered yet
+			time.Sleep(time.Duration(cfg.Circle.FetchRetryInterval) * time.Second)
+			processingQueue <- tx
+		case len(feeHeld) > 0:
+			holdForFees(logger, tx, feeHeld, true, metrics)
+		case held:
+			holdTx(logger, tx)
+		case retryLimit:
+			deadLetter(logger, tx, types.DeadLetterReasonRetryLimit, dequeuedTx.RetryAttempt, lastErr)
+		case broadcastFailed && a.DryRun:
+			// failed simulations are only reported, the tx is left for a real run to relay
+			logger.Info("Simulation failed for one or more messages, not dead lettering tx in dry run", "tx", tx.TxHash)
+		case broadcastFailed:
+			if lastErr == nil {
+				lastErr = errors.New("one or more messages failed to broadcast")
 			}
+			deadLetter(logger, tx, types.DeadLetterReasonBroadcast, dequeuedTx.RetryAttempt, lastErr)
 		}
 	}
 }
@@ -329,40 +511,3 @@ func filterLowTransfers(cfg *types.Config, logger log.Logger, msg *types.Message
 
 	return false
 }
-
-func startAPI(a *AppState) {
-	logger := a.Logger
-	cfg := a.Config
-	gin.SetMode(gin.ReleaseMode)
-	router := gin.Default()
-
-	err := router.SetTrustedProxies(cfg.API.TrustedProxies) // vpn.primary.strange.love
-	if err != nil {
-		logger.Error("Unable to set trusted proxies on API server: " + err.Error())
-		os.Exit(1)
-	}
-
-	router.GET("/tx/:txHash", getTxByHash)
-	err = router.Run("localhost:8000")
-	if err != nil {
-		logger.Error("Unable to start API server: " + err.Error())
-		os.Exit(1)
-	}
-}
-
-func getTxByHash(c *gin.Context) {
-	txHash := c.Param("txHash")
-
-	domain := c.Query("domain")
-	domainInt, err := strconv.ParseInt(domain, 10, 32)
-	if domain != "" && err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"message": "unable to parse domain"})
-	}
-
-	if tx, ok := State.Load(txHash); ok && domain == "" || (domain != "" && tx.Msgs[0].SourceDomain == types.Domain(uint32(domainInt))) {
-		c.JSON(http.StatusOK, tx.Msgs)
-		return
-	}
-
-	c.JSON(http.StatusNotFound, gin.H{"message": "message not found"})
-}
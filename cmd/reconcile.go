@@ -0,0 +1,418 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/relayer"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	defaultReconcileWindow    = 24 * time.Hour
+	defaultReconcileThreshold = 30 * time.Minute
+
+	// each Noble block is queried on its own, so the default Noble window is kept short
+	defaultNobleReconcileWindow = time.Hour
+
+	flagWindow    = "window"
+	flagThreshold = "threshold"
+)
+
+// unmintedMessage is a burn with no matching mint on its destination chain
+type unmintedMessage struct {
+	SourceDomain types.Domain `json:"source_domain"`
+	DestDomain   types.Domain `json:"dest_domain"`
+	Nonce        uint64       `json:"nonce"`
+	SourceTxHash string       `json:"source_tx_hash"`
+	IrisLookupID string       `json:"iris_lookup_id"`
+	// Status is the status of the message in the relayer's state, if it is tracked
+	Status string `json:"status,omitempty"`
+}
+
+// reconcileReport is the result of reconciling the burns of a source chain against their destination chains
+type reconcileReport struct {
+	Chain     string            `json:"chain"`
+	FromBlock uint64            `json:"from_block"`
+	ToBlock   uint64            `json:"to_block"`
+	Checked   int               `json:"checked"`
+	Unminted  []unmintedMessage `json:"unminted"`
+	Error     string            `json:"error,omitempty"`
+
+	// unmintedTxs holds the scanned txs of the unminted messages, with only those messages
+	unmintedTxs []*types.TxState
+}
+
+// Command for reporting burns that have no matching mint
+func reconcileCmd(a *AppState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reconcile",
+		Short: "Report burns that have no matching mint on their destination chain",
+		Long: strings.TrimSpace(fmt.Sprintf(`
+Report burns that have no matching mint on their destination chain.
+The MessageSent events of each source chain are scanned over the window ending at the threshold, and the used
+nonces of the destination chains are queried for each message. Messages the relayer would filter (disabled routes,
+min-mint-amount, destination caller) are not checked.
+The window and threshold default to the reconcile settings of the config, or %v (%v on Noble) and %v.
+Minter private keys are not needed, the chains are only queried.
+The command exits with an error if any unminted message is found.`, defaultReconcileWindow, defaultNobleReconcileWindow, defaultReconcileThreshold)),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s reconcile
+$ %s reconcile --chain ethereum --window 72h --threshold 1h --json`, appName, appName)),
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			a.InitAppState()
+		},
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := a.Logger
+			cfg := a.Config
+			ctx := cmd.Context()
+
+			jsn, err := cmd.Flags().GetBool(flagJSON)
+			if err != nil {
+				return err
+			}
+			name, err := cmd.Flags().GetString(flagChain)
+			if err != nil {
+				return err
+			}
+			settings := cfg.Reconcile
+			if cmd.Flags().Changed(flagWindow) {
+				if settings.Window, err = cmd.Flags().GetDuration(flagWindow); err != nil {
+					return err
+				}
+				// the flag applies to every chain
+				settings.Windows = nil
+			}
+			if cmd.Flags().Changed(flagThreshold) {
+				if settings.Threshold, err = cmd.Flags().GetDuration(flagThreshold); err != nil {
+					return err
+				}
+			}
+			if name != "" {
+				if _, ok := cfg.Chains[name]; !ok {
+					return fmt.Errorf("chain %q not found in config", name)
+				}
+			}
+
+			// every chain is a possible destination. The chains are only queried, so minter keys are not required
+			chains := make(map[types.Domain]types.Chain)
+			for chainName, chainCfg := range cfg.Chains {
+				c, err := chainCfg.QueryChain(chainName)
+				if err != nil {
+					return fmt.Errorf("error creating chain error=%w", err)
+				}
+				if err := c.InitializeClients(ctx, logger); err != nil {
+					return fmt.Errorf("error initializing client error=%w", err)
+				}
+				defer c.CloseClients()
+				chains[c.Domain()] = c
+			}
+
+			var reports []reconcileReport
+			for _, c := range chains {
+				if name != "" && c.Name() != name {
+					continue
+				}
+				reports = append(reports, reconcileChain(ctx, cfg, logger, c, chains, settings))
+			}
+			sort.Slice(reports, func(i, j int) bool { return reports[i].Chain < reports[j].Chain })
+
+			var unminted, failed int
+			for _, report := range reports {
+				unminted += len(report.Unminted)
+				if report.Error != "" {
+					failed++
+				}
+			}
+
+			if err := printReconcileReports(cmd, reports, jsn); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+			switch {
+			case failed > 0:
+				return fmt.Errorf("%d of %d chains could not be reconciled", failed, len(reports))
+			case unminted > 0:
+				return fmt.Errorf("%d unminted messages found", unminted)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String(flagChain, "", "name of the source chain to reconcile (all chains when empty)")
+	cmd.Flags().Duration(flagWindow, defaultReconcileWindow, "how far back burns are checked, counted from the threshold")
+	cmd.Flags().Duration(flagThreshold, defaultReconcileThreshold, "how old a burn must be before a missing mint is reported")
+
+	return addJSONFlag(cmd)
+}
+
+func printReconcileReports(cmd *cobra.Command, reports []reconcileReport, jsn bool) error {
+	if jsn {
+		out, err := json.Marshal(reports)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(out))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CHAIN\tBLOCKS\tCHECKED\tUNMINTED\tERROR")
+	for _, r := range reports {
+		fmt.Fprintf(w, "%s\t%d-%d\t%d\t%d\t%s\n", r.Chain, r.FromBlock, r.ToBlock, r.Checked, len(r.Unminted), r.Error)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	var unminted []unmintedMessage
+	for _, r := range reports {
+		unminted = append(unminted, r.Unminted...)
+	}
+	if len(unminted) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout())
+	w = tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tDEST\tNONCE\tSOURCE TX\tIRIS LOOKUP ID")
+	for _, msg := range unminted {
+		fmt.Fprintf(w, "%d\t%d\t%d\t%s\t0x%s\n", msg.SourceDomain, msg.DestDomain, msg.Nonce, msg.SourceTxHash, msg.IrisLookupID)
+	}
+	return w.Flush()
+}
+
+// startReconciler runs the reconciliation job on every registered chain at the configured interval.
+// Unminted messages are logged, counted in the unminted messages metric and, if configured, requeued.
+// A dry run only reports them, so that simulated messages are not reset and relayed again.
+func startReconciler(
+	ctx context.Context,
+	a *AppState,
+	registeredDomains map[types.Domain]types.Chain,
+	processingQueue chan *types.TxState,
+	metrics *relayer.PromMetrics,
+) {
+	logger := a.Logger.With("routine", "reconciler")
+	cfg := a.Config
+	settings := cfg.Reconcile
+	if settings.Interval == 0 {
+		logger.Info("Reconciliation job disabled in the config")
+		return
+	}
+
+	requeue := settings.Requeue && !a.DryRun
+	if settings.Requeue && a.DryRun {
+		logger.Info("Dry run, unminted messages are only reported and not requeued")
+	}
+	logger.Info(fmt.Sprintf("Starting reconciliation job. Will reconcile every %v", settings.Interval), "requeue", requeue)
+
+	for {
+		timer := time.NewTimer(settings.Interval)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		for _, c := range registeredDomains {
+			report := reconcileChain(ctx, cfg, logger, c, registeredDomains, settings)
+			if report.Error != "" {
+				logger.Error("Unable to reconcile chain", "chain", report.Chain, "err", report.Error)
+				continue
+			}
+
+			counts := make(map[string]int)
+			for _, dest := range cfg.EnabledRoutes[c.Domain()] {
+				counts[fmt.Sprint(dest)] = 0
+			}
+			for _, msg := range report.Unminted {
+				counts[fmt.Sprint(msg.DestDomain)]++
+				logger.Error(fmt.Sprintf("Found unminted message with nonce %d from %d to %d", msg.Nonce, msg.SourceDomain, msg.DestDomain),
+					"source_tx", msg.SourceTxHash, "status", msg.Status)
+			}
+			if metrics != nil {
+				metrics.SetUnminted(c.Name(), fmt.Sprint(c.Domain()), counts)
+			}
+			logger.Info(fmt.Sprintf("Reconciled %s blocks %d to %d: checked %d messages, %d unminted",
+				report.Chain, report.FromBlock, report.ToBlock, report.Checked, len(report.Unminted)))
+
+			if requeue {
+				for _, tx := range report.unmintedTxs {
+					requeueUnminted(logger, tx, processingQueue)
+				}
+			}
+		}
+	}
+}
+
+// reconcileChain scans the source chain's burns in the reconcile window and checks whether each was minted
+// on its destination chain. Errors are set on the report.
+func reconcileChain(
+	ctx context.Context,
+	cfg *types.Config,
+	logger log.Logger,
+	source types.Chain,
+	destinations map[types.Domain]types.Chain,
+	settings types.ReconcileSettings,
+) reconcileReport {
+	report := reconcileReport{Chain: source.Name(), Unminted: []unmintedMessage{}}
+	fail := func(err error) reconcileReport {
+		report.Error = err.Error()
+		return report
+	}
+
+	window, ok := settings.Windows[source.Name()]
+	if !ok {
+		window = settings.Window
+	}
+	if window == 0 && source.Domain() == nobleDomain {
+		window = defaultNobleReconcileWindow
+	}
+	if window == 0 {
+		window = defaultReconcileWindow
+	}
+	threshold := settings.Threshold
+	if threshold == 0 {
+		threshold = defaultReconcileThreshold
+	}
+
+	latest, err := source.QueryLatestBlock(ctx)
+	if err != nil {
+		return fail(fmt.Errorf("unable to query latest block: %w", err))
+	}
+	end := time.Now().Add(-threshold)
+	report.ToBlock, err = types.LastBlockBefore(ctx, source.BlockTime, latest, end)
+	if err != nil {
+		return fail(fmt.Errorf("unable to find the block before %v: %w", end, err))
+	}
+	before, err := types.LastBlockBefore(ctx, source.BlockTime, report.ToBlock, end.Add(-window))
+	if err != nil {
+		return fail(fmt.Errorf("unable to find the block before %v: %w", end.Add(-window), err))
+	}
+	report.FromBlock = before + 1
+	if report.ToBlock < report.FromBlock {
+		// the window holds no blocks yet
+		return report
+	}
+
+	processingQueue := make(chan *types.TxState, 100)
+	scanErr := make(chan error, 1)
+	go func() {
+		scanErr <- source.ConsumeHistory(ctx, logger, processingQueue, report.FromBlock, report.ToBlock)
+		close(processingQueue)
+	}()
+
+	// filtered messages are expected to stay unminted, and are not worth logging on every run
+	nop := log.NewNopLogger()
+	seen := make(map[uint64]struct{})
+	for tx := range processingQueue {
+		var unmintedMsgs []*types.MessageState
+		for _, msg := range tx.Msgs {
+			if _, ok := seen[msg.Nonce]; ok {
+				continue
+			}
+			seen[msg.Nonce] = struct{}{}
+
+			if FilterDisabledCCTPRoutes(cfg, nop, msg) ||
+				filterInvalidDestinationCallers(destinations, nop, msg) ||
+				filterLowTransfers(cfg, nop, msg) {
+				continue
+			}
+			report.Checked++
+
+			used, err := destinations[msg.DestDomain].NonceUsed(ctx, msg.SourceDomain, msg.Nonce)
+			if err != nil {
+				report.Error = fmt.Sprintf("unable to query used nonce %d on domain %d: %v", msg.Nonce, msg.DestDomain, err)
+				continue
+			}
+			if used {
+				continue
+			}
+
+			unminted := unmintedMessage{
+				SourceDomain: msg.SourceDomain,
+				DestDomain:   msg.DestDomain,
+				Nonce:        msg.Nonce,
+				SourceTxHash: msg.SourceTxHash,
+				IrisLookupID: msg.IrisLookupID,
+			}
+			if tracked, ok := State.Load(tx.TxHash); ok {
+				for _, trackedMsg := range tracked.Msgs {
+					if trackedMsg.Nonce == msg.Nonce {
+						unminted.Status = trackedMsg.Status
+					}
+				}
+			}
+			report.Unminted = append(report.Unminted, unminted)
+			unmintedMsgs = append(unmintedMsgs, msg)
+		}
+		if len(unmintedMsgs) > 0 {
+			report.unmintedTxs = append(report.unmintedTxs, &types.TxState{TxHash: tx.TxHash, Msgs: unmintedMsgs})
+		}
+	}
+
+	if err := <-scanErr; err != nil {
+		return fail(fmt.Errorf("unable to scan blocks %d to %d: %w", report.FromBlock, report.ToBlock, err))
+	}
+	return report
+}
+
+// requeueUnminted places a tx with unminted messages back on the processing queue.
+// Txs still in flight or waiting in the dead letter queue are left alone. Tracked txs that reached
+// a terminal status have their unminted messages reset, so the processor relays them again.
+func requeueUnminted(logger log.Logger, unminted *types.TxState, processingQueue chan *types.TxState) {
+	tx, ok := State.Load(unminted.TxHash)
+	if !ok {
+		logger.Info("Requeueing untracked tx with unminted messages", "tx", unminted.TxHash)
+		processingQueue <- unminted
+		return
+	}
+	if _, ok := deadLetters.Load(tx.TxHash); ok {
+		logger.Info("Not requeueing tx with unminted messages, it is waiting in the dead letter queue", "tx", tx.TxHash)
+		return
+	}
+	if !tx.IsTerminal() {
+		return
+	}
+
+	nonces := make(map[uint64]struct{})
+	for _, msg := range unminted.Msgs {
+		nonces[msg.Nonce] = struct{}{}
+	}
+
+	snapshot := statusSnapshot(tx)
+	State.Mu.Lock()
+	for _, msg := range tx.Msgs {
+		if _, ok := nonces[msg.Nonce]; !ok || msg.Status == types.Filtered {
+			continue
+		}
+		if msg.Attestation != "" {
+			msg.Status = types.Attested
+		} else {
+			msg.Status = types.Created
+		}
+		msg.Updated = time.Now()
+	}
+	tx.RetryAttempt = 0
+	State.Mu.Unlock()
+	publishTransitions(tx, snapshot)
+
+	if err := State.Store(tx.TxHash, tx); err != nil {
+		logger.Error("Unable to persist tx state", "tx", tx.TxHash, "err", err)
+	}
+
+	logger.Info("Requeueing tx with unminted messages", "tx", tx.TxHash)
+	processingQueue <- tx
+}
@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/circle"
+	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum"
+	"github.com/strangelove-ventures/noble-cctp-relayer/noble"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+// Command for relaying a single source tx without starting the listeners
+func relayCmd(a *AppState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "relay",
+		Short: "Relay the CCTP messages of a single source transaction",
+		Long: strings.TrimSpace(`
+Relay the CCTP messages of a single source transaction end-to-end.
+The messages are fetched from the source chain, the attestation is polled from Circle and the messages
+are broadcast to the destination chain. No listeners are started and the relayer's state is not used.`),
+		Example: strings.TrimSpace(fmt.Sprintf(`
+$ %s relay --domain 0 --tx 0xabc...
+$ %s relay --domain 4 --tx ABC...
+$ %s relay --domain 0 --tx 0xabc... --dry-run`, appName, appName, appName)),
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			a.InitAppState()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger := a.Logger
+			cfg := a.Config
+			ctx := cmd.Context()
+
+			domain, err := cmd.Flags().GetUint32(flagDomain)
+			if err != nil {
+				return err
+			}
+			txHash, err := cmd.Flags().GetString(flagTx)
+			if err != nil {
+				return err
+			}
+			dryRun, err := cmd.Flags().GetBool(flagDryRun)
+			if err != nil {
+				return err
+			}
+
+			source, err := queryChainByDomain(cfg, types.Domain(domain))
+			if err != nil {
+				return err
+			}
+			if err := source.InitializeClients(ctx, logger); err != nil {
+				return fmt.Errorf("error initializing client error=%w", err)
+			}
+			defer source.CloseClients()
+
+			tx, err := source.QueryTxMessages(ctx, logger, txHash)
+			if err != nil {
+				return err
+			}
+			logger.Info(fmt.Sprintf("Found %d CCTP messages in tx %s", len(tx.Msgs), tx.TxHash))
+
+			// the source chain cannot broadcast, so it is not reused as a destination
+			destinations := make(map[types.Domain]types.Chain)
+			broadcastMsgs := make(map[types.Domain][]*types.MessageState)
+			for _, msg := range tx.Msgs {
+				dest, ok := destinations[msg.DestDomain]
+				if !ok {
+					dest, err = chainByDomain(cfg, msg.DestDomain)
+					if err != nil {
+						return err
+					}
+					if err := dest.InitializeClients(ctx, logger); err != nil {
+						return fmt.Errorf("error initializing client error=%w", err)
+					}
+					defer dest.CloseClients()
+					destinations[msg.DestDomain] = dest
+				}
+
+				if ok, caller := dest.IsDestinationCaller(msg.DestinationCaller); !ok {
+					return fmt.Errorf("message with nonce %d can only be received by destination caller %s", msg.Nonce, caller)
+				}
+
+				if err := pollAttestation(cfg, logger, msg); err != nil {
+					return err
+				}
+				broadcastMsgs[msg.DestDomain] = append(broadcastMsgs[msg.DestDomain], msg)
+			}
+
+			sequenceMap := types.NewSequenceMap()
+			for destDomain, msgs := range broadcastMsgs {
+				dest := destinations[destDomain]
+				if err := dest.InitializeBroadcaster(ctx, logger, sequenceMap); err != nil {
+					return fmt.Errorf("error initializing broadcaster error=%w", err)
+				}
+				if dryRun {
+					if err := dest.Simulate(ctx, logger, msgs); err != nil {
+						return fmt.Errorf("unable to simulate on %s error=%w", dest.Name(), err)
+					}
+					continue
+				}
+				if err := dest.Broadcast(ctx, logger, msgs, sequenceMap, nil); err != nil {
+					return fmt.Errorf("unable to broadcast to %s error=%w", dest.Name(), err)
+				}
+			}
+
+			for _, msg := range tx.Msgs {
+				switch {
+				case msg.DestTxHash != "":
+					fmt.Fprintf(cmd.OutOrStdout(), "nonce %d from %d to %d relayed in tx %s\n", msg.Nonce, msg.SourceDomain, msg.DestDomain, msg.DestTxHash)
+				case msg.Status == types.Simulated:
+					fmt.Fprintf(cmd.OutOrStdout(), "nonce %d from %d to %d would be relayed (dry run)\n", msg.Nonce, msg.SourceDomain, msg.DestDomain)
+				case msg.Status == types.Complete:
+					fmt.Fprintf(cmd.OutOrStdout(), "nonce %d from %d to %d was already relayed\n", msg.Nonce, msg.SourceDomain, msg.DestDomain)
+				default:
+					fmt.Fprintf(cmd.OutOrStdout(), "nonce %d from %d to %d was not relayed, status: %s\n", msg.Nonce, msg.SourceDomain, msg.DestDomain, msg.Status)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Uint32(flagDomain, 0, "domain of the source chain")
+	cmd.Flags().String(flagTx, "", "hash of the source transaction")
+	_ = cmd.MarkFlagRequired(flagDomain)
+	_ = cmd.MarkFlagRequired(flagTx)
+
+	return addDryRunFlag(cmd)
+}
+
+// pollAttestation queries Circle for the message's attestation until it is complete, using the configured
+// circle fetch retries and interval. The attestation is set on the message.
+func pollAttestation(cfg *types.Config, logger log.Logger, msg *types.MessageState) error {
+	for attempt := 0; attempt <= cfg.Circle.FetchRetries; attempt++ {
+		response := circle.CheckAttestation(cfg.Circle.AttestationBaseURL, logger, msg.IrisLookupID, msg.SourceTxHash, msg.SourceDomain, msg.DestDomain)
+		if response != nil && response.Status == "complete" {
+			msg.Status = types.Attested
+			msg.Attestation = response.Attestation
+			msg.Updated = time.Now()
+			return nil
+		}
+
+		if attempt < cfg.Circle.FetchRetries {
+			logger.Info(fmt.Sprintf("Attestation is not complete yet for 0x%s. Retrying in %d seconds...", msg.IrisLookupID, cfg.Circle.FetchRetryInterval))
+			time.Sleep(time.Duration(cfg.Circle.FetchRetryInterval) * time.Second)
+		}
+	}
+	return fmt.Errorf("attestation for 0x%s not complete after %d retries", msg.IrisLookupID, cfg.Circle.FetchRetries)
+}
+
+// chainByDomain creates the configured chain with the given domain.
+// Only that chain is created, so only its minter private key is needed.
+func chainByDomain(cfg *types.Config, domain types.Domain) (types.Chain, error) {
+	name, chainCfg, err := chainConfigByDomain(cfg, domain)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := chainCfg.Chain(name)
+	if err != nil {
+		return nil, fmt.Errorf("error creating chain error=%w", err)
+	}
+	return chain, nil
+}
+
+// queryChainByDomain creates the configured chain with the given domain without its minter private key.
+// The chain can only be queried, ex. as the source of a message.
+func queryChainByDomain(cfg *types.Config, domain types.Domain) (types.Chain, error) {
+	name, chainCfg, err := chainConfigByDomain(cfg, domain)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := chainCfg.QueryChain(name)
+	if err != nil {
+		return nil, fmt.Errorf("error creating chain error=%w", err)
+	}
+	return chain, nil
+}
+
+// chainConfigByDomain returns the name and config of the chain with the given domain.
+func chainConfigByDomain(cfg *types.Config, domain types.Domain) (string, types.ChainConfig, error) {
+	for name, chainCfg := range cfg.Chains {
+		if d, ok := chainConfigDomain(chainCfg); ok && d == domain {
+			return name, chainCfg, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no chain configured for domain %d", domain)
+}
+
+// chainConfigDomain returns the domain of a chain config without creating the chain.
+func chainConfigDomain(chainCfg types.ChainConfig) (types.Domain, bool) {
+	switch c := chainCfg.(type) {
+	case *noble.ChainConfig:
+		return 4, true
+	case *ethereum.ChainConfig:
+		return c.Domain, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/store"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const defaultSweepInterval = 10 * time.Minute
+
+// archive holds tx states evicted from the State. It is nil when no archive file is configured.
+var archive *store.Archive
+
+// startRetention opens the archive file (if configured) and starts the background sweeper
+// that evicts terminal tx states from the State according to the retention policy.
+func startRetention(ctx context.Context, logger log.Logger, cfg types.RetentionSettings) error {
+	if cfg.ArchiveFile != "" {
+		var err error
+		archive, err = store.NewArchive(cfg.ArchiveFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.TTL == 0 && cfg.MaxEntries == 0 {
+		logger.Info("No state retention policy configured. Terminal message states will be kept forever")
+		return nil
+	}
+
+	interval := cfg.SweepInterval
+	if interval == 0 {
+		interval = defaultSweepInterval
+	}
+
+	logger.Info(fmt.Sprintf("Starting state sweeper. Will sweep every %v", interval), "ttl", cfg.TTL, "max_entries", cfg.MaxEntries, "archive_file", cfg.ArchiveFile)
+
+	go func() {
+		for {
+			timer := time.NewTimer(interval)
+			select {
+			case <-timer.C:
+				sweepState(logger, cfg)
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// sweepState evicts terminal tx states that fall outside of the retention policy.
+// Evicted tx states are appended to the archive before being dropped, if an archive is configured.
+func sweepState(logger log.Logger, cfg types.RetentionSettings) {
+	var evicted int
+	for _, tx := range State.Evictable(cfg.TTL, cfg.MaxEntries, time.Now()) {
+		if archive != nil {
+			// the tx state is encoded while State.Mu is held, so it is not mutated meanwhile
+			State.Mu.Lock()
+			err := archive.Append(tx)
+			State.Mu.Unlock()
+			if err != nil {
+				// keep the tx state rather than losing it
+				logger.Error("Unable to archive tx state, skipping eviction", "tx", tx.TxHash, "err", err)
+				continue
+			}
+		}
+		if err := State.Delete(tx.TxHash); err != nil {
+			logger.Error("Unable to evict tx state", "tx", tx.TxHash, "err", err)
+			continue
+		}
+		evicted++
+	}
+
+	if evicted > 0 {
+		logger.Info(fmt.Sprintf("Evicted %d terminal tx states from state", evicted))
+	}
+}
+
+// closeArchive closes the archive file, if one is open.
+func closeArchive() error {
+	if archive != nil {
+		return archive.Close()
+	}
+	return nil
+}
@@ -26,6 +26,13 @@ func NewRootCmd() *cobra.Command {
 		Start(a),
 		getVersionCmd(),
 		configShowCmd(a),
+		configCmd(a),
+		keysCmd(a),
+		decodeCmd(),
+		dlqCmd(),
+		relayCmd(a),
+		backfillCmd(a),
+		reconcileCmd(a),
 	)
 
 	addAppPersistantFlags(rootCmd, a)
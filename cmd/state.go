@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/store"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+// checkpointFlushInterval is how often listener block checkpoints are written to disk
+const checkpointFlushInterval = 15 * time.Second
+
+// initState replaces the in-memory State, Checkpoints and dead letter queue with ones backed by the configured persistence backend.
+func initState(cfg types.StateSettings, logger log.Logger) error {
+	switch cfg.Backend {
+	case "", types.StateBackendMemory:
+		logger.Info("Using in-memory state. Message state, block checkpoints and dead letters will not survive a restart")
+		return nil
+	case types.StateBackendBolt:
+		s, err := store.NewBoltStore(cfg.Path)
+		if err != nil {
+			return err
+		}
+		State, err = types.NewPersistentStateMap(s)
+		if err != nil {
+			s.Close()
+			return fmt.Errorf("unable to load state from %s: %w", cfg.Path, err)
+		}
+		checkpoints, err = types.NewPersistentCheckpoints(s)
+		if err != nil {
+			s.Close()
+			return fmt.Errorf("unable to load block checkpoints from %s: %w", cfg.Path, err)
+		}
+		deadLetters, err = types.NewPersistentDeadLetterQueue(s)
+		if err != nil {
+			s.Close()
+			return fmt.Errorf("unable to load dead letters from %s: %w", cfg.Path, err)
+		}
+		logger.Info("Loaded persisted state", "backend", cfg.Backend, "path", cfg.Path)
+		return nil
+	default:
+		return fmt.Errorf("unknown state backend: %s", cfg.Backend)
+	}
+}
+
+// requeueInFlight places every non-terminal TxState from the State back onto the processing queue.
+// It is used on startup to resume transfers that were in flight when the relayer stopped.
+func requeueInFlight(logger log.Logger, processingQueue chan *types.TxState) {
+	var requeued int
+	State.Range(func(txHash string, tx *types.TxState) bool {
+		if tx.IsTerminal() {
+			return true
+		}
+		// dead lettered txs wait for an operator to replay them
+		if _, ok := deadLetters.Load(txHash); ok {
+			return true
+		}
+		tx.RetryAttempt = 0
+		processingQueue <- tx
+		requeued++
+		return true
+	})
+	if requeued > 0 {
+		logger.Info(fmt.Sprintf("Requeued %d in-flight txs from persisted state", requeued))
+	}
+}
+
+// flushCheckpoints periodically writes the listeners' block checkpoints to disk until the context is done.
+func flushCheckpoints(ctx context.Context, logger log.Logger) {
+	for {
+		timer := time.NewTimer(checkpointFlushInterval)
+		select {
+		case <-timer.C:
+			if err := checkpoints.Flush(); err != nil {
+				logger.Error("Unable to persist block checkpoints", "err", err)
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
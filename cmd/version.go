@@ -39,16 +39,7 @@ $ %s v`,
 				return err
 			}
 
-			commit := Commit
-			if Dirty != "0" {
-				commit += " (dirty)"
-			}
-
-			verInfo := versionInfo{
-				Version: Version,
-				Commit:  commit,
-				Go:      fmt.Sprintf("%s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH),
-			}
+			verInfo := getVersionInfo()
 
 			var bz []byte
 			if jsn {
@@ -63,3 +54,17 @@ $ %s v`,
 	}
 	return addJSONFlag(versionCmd)
 }
+
+// getVersionInfo returns the build info of the running binary
+func getVersionInfo() versionInfo {
+	commit := Commit
+	if Dirty != "0" {
+		commit += " (dirty)"
+	}
+
+	return versionInfo{
+		Version: Version,
+		Commit:  commit,
+		Go:      fmt.Sprintf("%s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH),
+	}
+}
@@ -10,6 +10,8 @@ import (
 	rpcclient "github.com/cometbft/cometbft/rpc/client"
 	coretypes "github.com/cometbft/cometbft/rpc/core/types"
 
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+
 	"github.com/strangelove-ventures/noble-cctp-relayer/types"
 )
 
@@ -77,3 +79,8 @@ func (cc *CosmosProvider) GetBlockAtHeight(ctx context.Context, height int64) (*
 	}
 	return block, nil
 }
+
+// SimulateTx simulates a signed tx through the tx service, without broadcasting it
+func (cc *CosmosProvider) SimulateTx(ctx context.Context, txBytes []byte) (*txtypes.SimulateResponse, error) {
+	return txtypes.NewServiceClient(cc).Simulate(ctx, &txtypes.SimulateRequest{TxBytes: txBytes})
+}
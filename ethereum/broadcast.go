@@ -6,12 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
-	"regexp"
-	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"cosmossdk.io/log"
@@ -26,11 +26,20 @@ func (e *Ethereum) InitializeBroadcaster(
 	logger log.Logger,
 	sequenceMap *types.SequenceMap,
 ) error {
-	nextNonce, err := GetEthereumAccountNonce(e.rpcURL, e.minterAddress)
+	nextNonce, err := e.rpcClient.PendingNonceAt(ctx, common.HexToAddress(e.minterAddress))
 	if err != nil {
 		return fmt.Errorf("unable to retrieve evm account nonce: %w", err)
 	}
-	sequenceMap.Put(e.Domain(), uint64(nextNonce))
+	sequenceMap.Put(e.Domain(), nextNonce)
+
+	if e.bumpTimeout > 0 {
+		e.replaceOnce.Do(func() {
+			go e.replaceStuckTxs(ctx, logger)
+		})
+	}
+	e.fillOnce.Do(func() {
+		go e.fillNonceGaps(ctx, logger, sequenceMap)
+	})
 
 	return nil
 }
@@ -56,7 +65,27 @@ func (e *Ethereum) Broadcast(
 		return fmt.Errorf("unable to create message transmitter: %w", err)
 	}
 
-	var broadcastErrors error
+	var multicallPending bool
+	if e.multicallAddress != "" {
+		err := e.submitMulticall(ctx, logger, msgs, sequenceMap)
+		switch {
+		case errors.Is(err, types.ErrFeeCapExceeded):
+			logger.Info(fmt.Sprintf("Not minting %d messages on %s: %s", len(msgs), e.name, err))
+			return err
+		case errors.Is(err, errTxPending):
+			// the multicall tx may still be mined, its messages are waited on again once requeued
+			multicallPending = true
+		case err != nil && ctx.Err() != nil:
+			// the batch is still being minted without this broadcast, so its messages are not minted individually
+			return fmt.Errorf("stopped waiting on the multicall mint: %w", err)
+		case err != nil:
+			// messages the multicall did not mint are broadcast individually below
+			logger.Error("Multicall mint failed, minting the messages it did not mint individually", "err", err)
+		}
+	}
+
+	// pending mints are only reported if nothing else failed, so that waiting on them does not count as a retry
+	var broadcastErrors, pendingErrors error
 MsgLoop:
 	for _, msg := range msgs {
 		attestationBytes, err := hex.DecodeString(msg.Attestation[2:])
@@ -70,17 +99,72 @@ MsgLoop:
 				continue MsgLoop
 			}
 
-			if err := e.attemptBroadcast(
-				ctx,
-				logger,
-				msg,
-				sequenceMap,
-				auth,
-				messageTransmitter,
-				attestationBytes,
-			); err == nil {
+			// a tx submitted for the message in an earlier attempt may still be mined, so it is waited on instead
+			// of sending another
+			var err error
+			p := e.pending.find(msg)
+			if p != nil && p.multicall() && multicallPending {
+				pendingErrors = errors.Join(pendingErrors, fmt.Errorf("%w: multicall tx %s", errTxPending, p.latest().Hash().Hex()))
 				continue MsgLoop
 			}
+			if p == nil {
+				var tx *ethtypes.Transaction
+				tx, err = e.attemptBroadcast(
+					ctx,
+					logger,
+					msg,
+					sequenceMap,
+					auth,
+					messageTransmitter,
+					attestationBytes,
+				)
+				if errors.Is(err, types.ErrFeeCapExceeded) {
+					// the remaining messages stay attested, to be broadcast once the fee drops
+					logger.Info(fmt.Sprintf("Not minting %s on %s: %s", msg.SourceTxHash, e.name, err))
+					return errors.Join(broadcastErrors, err)
+				}
+				if err == nil {
+					p = e.pending.track([]*types.MessageState{msg}, tx)
+				}
+			}
+
+			// the tx is tracked outside of attemptBroadcast, so that other messages can be broadcast meanwhile
+			if p != nil {
+				err = e.awaitReceipt(ctx, logger, msg, auth.From, p, sequenceMap)
+			}
+			if err == nil {
+				if msg.Status == types.Complete {
+					continue MsgLoop
+				}
+				// the multicall tx was confirmed without minting the message, it is minted on its own
+				continue
+			}
+			if errors.Is(err, errTxPending) {
+				// the message is requeued and its tx waited on again
+				logger.Info(fmt.Sprintf("Mint of %s is still pending", msg.SourceTxHash), "err", err)
+				pendingErrors = errors.Join(pendingErrors, err)
+				continue MsgLoop
+			}
+
+			var revertErr *RevertError
+			if errors.As(err, &revertErr) {
+				switch revertErr.action() {
+				case actionComplete:
+					// the message was received by another tx first
+					logger.Info(fmt.Sprintf("This source domain/nonce has already been used: %d %d", msg.SourceDomain, msg.Nonce), "src-tx", msg.SourceTxHash)
+					msg.Status = types.Complete
+					continue MsgLoop
+				case actionFail:
+					logger.Error(fmt.Sprintf("Mint of %s reverted: %s", msg.SourceTxHash, revertErr.Reason), "tx", revertErr.TxHash)
+					msg.Status = types.Failed
+					if m != nil {
+						m.IncBroadcastErrors(e.name, fmt.Sprint(e.domain))
+					}
+					broadcastErrors = errors.Join(broadcastErrors, err)
+					continue MsgLoop
+				}
+			}
+			logger.Error(fmt.Sprintf("Mint of %s was not confirmed", msg.SourceTxHash), "err", err)
 
 			// if it's not the last attempt, retry
 			// TODO increase the destination.ethereum.broadcast retries (3-5) and retry interval (15s).  By checking for used nonces, there is no gas cost for failed mints.
@@ -96,7 +180,10 @@ MsgLoop:
 		}
 		broadcastErrors = errors.Join(broadcastErrors, errors.New("reached max number of broadcast attempts"))
 	}
-	return broadcastErrors
+	if broadcastErrors != nil {
+		return broadcastErrors
+	}
+	return pendingErrors
 }
 
 func (e *Ethereum) attemptBroadcast(
@@ -107,90 +194,168 @@ func (e *Ethereum) attemptBroadcast(
 	auth *bind.TransactOpts,
 	messageTransmitter *contracts.MessageTransmitter,
 	attestationBytes []byte,
-) error {
+) (*ethtypes.Transaction, error) {
 	logger.Info(fmt.Sprintf(
 		"Broadcasting message from %d to %d: with source tx hash %s",
 		msg.SourceDomain,
 		msg.DestDomain,
 		msg.SourceTxHash))
 
-	nonce := sequenceMap.Next(e.domain)
-	auth.Nonce = big.NewInt(int64(nonce))
-
-	e.mu.Lock()
-	defer e.mu.Unlock()
-
-	// TODO remove
-	nextNonce, err := GetEthereumAccountNonce(e.rpcURL, e.minterAddress)
+	// estimate and simulate the call before signing, so reverts are caught without spending gas
+	logger.Debug("Simulating ReceiveMessage", "source_domain", msg.SourceDomain, "nonce", msg.Nonce)
+	gas, err := e.simulateReceive(ctx, auth.From, msg.MsgSentBytes, attestationBytes)
 	if err != nil {
-		logger.Error("unable to retrieve account number")
-	} else {
-		auth.Nonce = big.NewInt(nextNonce)
+		return nil, err
 	}
-	// TODO end remove
+	auth.GasLimit = gas
 
-	// check if nonce already used
-	co := &bind.CallOpts{
-		Pending: true,
-		Context: ctx,
+	fees, err := e.fees(ctx)
+	if err != nil {
+		return nil, err
 	}
+	fees.apply(auth)
 
-	logger.Debug("Checking if nonce was used for broadcast to Ethereum", "source_domain", msg.SourceDomain, "nonce", msg.Nonce)
-
-	key := append(
-		common.LeftPadBytes((big.NewInt(int64(msg.SourceDomain))).Bytes(), 4),
-		common.LeftPadBytes((big.NewInt(int64(msg.Nonce))).Bytes(), 8)...,
-	)
-
-	response, nonceErr := messageTransmitter.UsedNonces(co, [32]byte(crypto.Keccak256(key)))
-	if nonceErr != nil {
-		logger.Debug("Error querying whether nonce was used.   Continuing...", "error:", nonceErr)
-	} else if response.Uint64() == uint64(1) {
-		// nonce has already been used, mark as complete
-		logger.Debug(fmt.Sprintf("This source domain/nonce has already been used: %d %d",
-			msg.SourceDomain, msg.Nonce), "src-tx", msg.SourceTxHash, "reviever")
-		msg.Status = types.Complete
-		return nil
-	}
+	// the account nonce is allocated locally, so txs can be signed concurrently
+	nonce := sequenceMap.Next(e.domain)
+	auth.Nonce = new(big.Int).SetUint64(nonce)
 
-	// broadcast txn
+	// sign the tx without sending it, so its cost can be checked first
+	auth.NoSend = true
 	tx, err := messageTransmitter.ReceiveMessage(
 		auth,
 		msg.MsgSentBytes,
 		attestationBytes,
 	)
 	if err == nil {
-		msg.Status = types.Complete
+		if err := e.checkMintCost(tx.Gas(), fees); err != nil {
+			sequenceMap.Release(e.domain, nonce)
+			return nil, err
+		}
 
+		// broadcast txn
+		err = e.rpcClient.SendTransaction(ctx, tx)
+	}
+	if err == nil {
 		msg.DestTxHash = tx.Hash().Hex()
 
-		logger.Info(fmt.Sprintf("Successfully broadcast %s to Ethereum.  Tx hash: %s", msg.SourceTxHash, msg.DestTxHash))
+		logger.Info(fmt.Sprintf("Successfully broadcast %s to Ethereum.  Tx hash: %s. Waiting for %d confirmations", msg.SourceTxHash, msg.DestTxHash, e.confirmations))
 
-		return nil
+		return tx, nil
 	}
 
 	logger.Error(fmt.Sprintf("error during broadcast: %s", err.Error()))
-	if parsedErr, ok := err.(JSONError); ok {
-		if parsedErr.ErrorCode() == 3 && parsedErr.Error() == "execution reverted: Nonce already used" {
-			msg.Status = types.Complete
-			logger.Error(fmt.Sprintf("This account nonce has already been used: %d", nonce))
+	e.handleSendError(ctx, logger, sequenceMap, nonce, err)
+
+	return nil, err
+}
+
+// handleSendError updates the sequence map after signing or sending the tx with the account nonce failed.
+func (e *Ethereum) handleSendError(ctx context.Context, logger log.Logger, sequenceMap *types.SequenceMap, nonce uint64, err error) {
+	if accountNonceTaken(err) {
+		// the account nonce was used by another tx, continue from the pending nonce
+		e.reconcileNonce(ctx, logger, sequenceMap)
+		return
+	}
+	// no tx was submitted with the account nonce, so it is allocated again
+	sequenceMap.Release(e.domain, nonce)
+}
+
+// accountNonceTaken reports whether a tx was rejected because its account nonce was already used by another tx,
+// either mined or pending.
+func accountNonceTaken(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "replacement transaction underpriced") ||
+		strings.Contains(msg, "already known")
+}
 
-			return nil
+// reconcileNonce resets the chain's next account nonce to the pending nonce of the minter.
+func (e *Ethereum) reconcileNonce(ctx context.Context, logger log.Logger, sequenceMap *types.SequenceMap) {
+	nextNonce, err := e.rpcClient.PendingNonceAt(ctx, common.HexToAddress(e.minterAddress))
+	if err != nil {
+		logger.Error("unable to retrieve account nonce", "err", err)
+		return
+	}
+	logger.Debug(fmt.Sprintf("Reconciled account nonce with pending nonce %d", nextNonce))
+	sequenceMap.Put(e.domain, nextNonce)
+}
+
+// Simulate simulates a ReceiveMessage tx for each message with eth_call and signs it, without sending it.
+// The tx is signed with the pending account nonce, so the relayer's sequence map is left untouched.
+func (e *Ethereum) Simulate(
+	ctx context.Context,
+	logger log.Logger,
+	msgs []*types.MessageState,
+) error {
+	logger = logger.With("chain", e.name, "chain_id", e.chainID, "domain", e.domain)
+
+	auth, err := bind.NewKeyedTransactorWithChainID(e.privateKey, big.NewInt(e.chainID))
+	if err != nil {
+		return fmt.Errorf("unable to create auth: %w", err)
+	}
+	auth.Context = ctx
+	auth.NoSend = true
+
+	messageTransmitter, err := contracts.NewMessageTransmitter(common.HexToAddress(e.messageTransmitterAddress), NewContractBackendWrapper(e.rpcClient))
+	if err != nil {
+		return fmt.Errorf("unable to create message transmitter: %w", err)
+	}
+
+	var simulationErrors error
+	for _, msg := range msgs {
+		attestationBytes, err := hex.DecodeString(msg.Attestation[2:])
+		if err != nil {
+			return errors.New("unable to decode message attestation")
 		}
 
-		match, _ := regexp.MatchString("nonce too low: next nonce [0-9]+, tx nonce [0-9]+", parsedErr.Error())
-		if match {
-			numberRegex := regexp.MustCompile("[0-9]+")
-			nextNonce, err := strconv.ParseInt(numberRegex.FindAllString(parsedErr.Error(), 1)[0], 10, 0)
-			if err != nil {
-				nextNonce, err = GetEthereumAccountNonce(e.rpcURL, e.minterAddress)
-				if err != nil {
-					logger.Error("unable to retrieve account number")
-				}
-			}
-			sequenceMap.Put(e.domain, uint64(nextNonce))
+		used, err := e.NonceUsed(ctx, msg.SourceDomain, msg.Nonce)
+		if err != nil {
+			simulationErrors = errors.Join(simulationErrors, err)
+			continue
+		}
+		if used {
+			msg.Status = types.Complete
+			continue
 		}
+
+		// the call is simulated like before a broadcast, then signed with its gas estimate
+		var tx *ethtypes.Transaction
+		auth.GasLimit, err = e.simulateReceive(ctx, auth.From, msg.MsgSentBytes, attestationBytes)
+		if err == nil {
+			tx, err = messageTransmitter.ReceiveMessage(auth, msg.MsgSentBytes, attestationBytes)
+		}
+		if err != nil {
+			logger.Error(fmt.Sprintf("Dry run: simulating %s on %s failed", msg.SourceTxHash, e.name), "err", err)
+			msg.Status = types.Failed
+			simulationErrors = errors.Join(simulationErrors, fmt.Errorf("simulation failed: %w", err))
+			continue
+		}
+
+		msg.Status = types.Simulated
+		logger.Info(fmt.Sprintf("Dry run: simulated %s on %s. Gas: %d, signed tx hash: %s", msg.SourceTxHash, e.name, tx.Gas(), tx.Hash().Hex()))
 	}
+	return simulationErrors
+}
+
+// NonceUsed queries the MessageTransmitter for whether the message with the source domain and nonce was received.
+func (e *Ethereum) NonceUsed(ctx context.Context, sourceDomain types.Domain, nonce uint64) (bool, error) {
+	messageTransmitter, err := contracts.NewMessageTransmitter(common.HexToAddress(e.messageTransmitterAddress), e.rpcClient)
+	if err != nil {
+		return false, fmt.Errorf("unable to create message transmitter: %w", err)
+	}
+
+	response, err := messageTransmitter.UsedNonces(&bind.CallOpts{Context: ctx}, usedNonceKey(sourceDomain, nonce))
+	if err != nil {
+		return false, fmt.Errorf("unable to query used nonce: %w", err)
+	}
+	return response.Uint64() == uint64(1), nil
+}
 
-	return err
+// usedNonceKey returns the MessageTransmitter usedNonces key of a message: keccak256(sourceDomain, nonce)
+func usedNonceKey(sourceDomain types.Domain, nonce uint64) [32]byte {
+	key := append(
+		common.LeftPadBytes((big.NewInt(int64(sourceDomain))).Bytes(), 4),
+		common.LeftPadBytes((big.NewInt(int64(nonce))).Bytes(), 8)...,
+	)
+	return [32]byte(crypto.Keccak256(key))
 }
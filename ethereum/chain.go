@@ -6,14 +6,21 @@ import (
 	"crypto/ecdsa"
 	"embed"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/params"
 
 	"cosmossdk.io/log"
 
+	"github.com/strangelove-ventures/noble-cctp-relayer/relayer"
 	"github.com/strangelove-ventures/noble-cctp-relayer/types"
 )
 
@@ -22,6 +29,9 @@ var content embed.FS
 
 var _ types.Chain = (*Ethereum)(nil)
 
+// maxLatestBlockAge is how long the latest block height may go without increasing before the chain is reported unhealthy
+const maxLatestBlockAge = 5 * time.Minute
+
 type Ethereum struct {
 	// from config
 	name                      string
@@ -36,17 +46,48 @@ type Ethereum struct {
 	minterAddress             string
 	maxRetries                int
 	retryIntervalSeconds      int
+	confirmations             uint64
+	receiptTimeout            time.Duration
+	bumpTimeout               time.Duration
+	maxFeeCap                 *big.Int
+	feeMode                   string
+	tipMultiplier             float64
+	feeMultiplier             float64
+	maxMintCost               *big.Int
+	multicallAddress          string
+	multicallWindow           time.Duration
+	multicallMaxMsgs          int
 	minAmount                 uint64
 	MetricsDenom              string
 	MetricsExponent           int
 
 	mu sync.Mutex
 
+	// pending are the submitted mint txs that are not confirmed yet, by account nonce
+	pending *pendingTxs
+	// replaceOnce starts replacing stuck pending txs once
+	replaceOnce sync.Once
+	// fillOnce starts filling account nonce gaps once
+	fillOnce sync.Once
+
+	// multicallBatcher collects the msgs of concurrent broadcasts into multicalls
+	multicallBatcher *relayer.Batcher[*types.MessageState]
+	multicallOnce    sync.Once
+
 	wsClient  *ethclient.Client
 	rpcClient *ethclient.Client
 
 	latestBlock      uint64
 	lastFlushedBlock uint64
+
+	// latestBlockIncreased is when the latest block last increased
+	latestBlockIncreased time.Time
+
+	// streaming is set when the listener runs the websocket subscription, streamAlive while it is connected
+	streaming   bool
+	streamAlive bool
+
+	flushTrigger chan struct{}
 }
 
 func NewChain(
@@ -61,13 +102,48 @@ func NewChain(
 	privateKey string,
 	maxRetries int,
 	retryIntervalSeconds int,
+	confirmations uint64,
+	receiptTimeout time.Duration,
+	bumpTimeout time.Duration,
+	maxFeeGwei uint64,
+	feeMode string,
+	tipMultiplier float64,
+	feeMultiplier float64,
+	maxMintCostGwei uint64,
+	multicallAddress string,
+	multicallWindow time.Duration,
+	multicallMaxMsgs int,
 	minAmount uint64,
 	metricsDenom string,
 	metricsExponent int,
 ) (*Ethereum, error) {
-	privEcdsaKey, ethereumAddress, err := GetEcdsaKeyAddress(privateKey)
-	if err != nil {
-		return nil, err
+	// a chain without a private key can only be queried
+	var privEcdsaKey *ecdsa.PrivateKey
+	var ethereumAddress string
+	if privateKey != "" {
+		var err error
+		privEcdsaKey, ethereumAddress, err = GetEcdsaKeyAddress(privateKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if confirmations == 0 {
+		confirmations = defaultConfirmations
+	}
+	if receiptTimeout == 0 {
+		receiptTimeout = defaultReceiptTimeout
+	}
+	if feeMode == "" {
+		feeMode = FeeModeDynamic
+	}
+	if tipMultiplier == 0 {
+		tipMultiplier = defaultTipMultiplier
+	}
+	if multicallWindow == 0 {
+		multicallWindow = defaultMulticallWindow
+	}
+	if multicallMaxMsgs == 0 {
+		multicallMaxMsgs = defaultMulticallMaxMsgs
 	}
 	return &Ethereum{
 		name:                      name,
@@ -82,9 +158,22 @@ func NewChain(
 		minterAddress:             ethereumAddress,
 		maxRetries:                maxRetries,
 		retryIntervalSeconds:      retryIntervalSeconds,
+		confirmations:             confirmations,
+		receiptTimeout:            receiptTimeout,
+		bumpTimeout:               bumpTimeout,
+		maxFeeCap:                 new(big.Int).Mul(new(big.Int).SetUint64(maxFeeGwei), big.NewInt(params.GWei)),
+		feeMode:                   feeMode,
+		tipMultiplier:             tipMultiplier,
+		feeMultiplier:             feeMultiplier,
+		maxMintCost:               new(big.Int).Mul(new(big.Int).SetUint64(maxMintCostGwei), big.NewInt(params.GWei)),
+		multicallAddress:          multicallAddress,
+		multicallWindow:           multicallWindow,
+		multicallMaxMsgs:          multicallMaxMsgs,
 		minAmount:                 minAmount,
 		MetricsDenom:              metricsDenom,
 		MetricsExponent:           metricsExponent,
+		pending:                   newPendingTxs(),
+		flushTrigger:              make(chan struct{}, 1),
 	}, nil
 }
 
@@ -105,10 +194,102 @@ func (e *Ethereum) LatestBlock() uint64 {
 
 func (e *Ethereum) SetLatestBlock(block uint64) {
 	e.mu.Lock()
+	if block > e.latestBlock {
+		e.latestBlockIncreased = time.Now()
+	}
 	e.latestBlock = block
 	e.mu.Unlock()
 }
 
+func (e *Ethereum) setStreamAlive(alive bool) {
+	e.mu.Lock()
+	e.streaming = true
+	e.streamAlive = alive
+	e.mu.Unlock()
+}
+
+// CheckHealth ensures the latest block height is advancing and the websocket subscription is connected.
+func (e *Ethereum) CheckHealth(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.latestBlock == 0 {
+		return errors.New("latest block height not queried yet")
+	}
+	if age := time.Since(e.latestBlockIncreased); age > maxLatestBlockAge {
+		return fmt.Errorf("latest block %d has not increased in %s", e.latestBlock, age.Round(time.Second))
+	}
+	if e.streaming && !e.streamAlive {
+		return errors.New("websocket subscription is disconnected")
+	}
+	return nil
+}
+
+// CheckConfig ensures the rpc and websocket endpoints serve the configured chain id,
+// the MessageTransmitter address has contract code and the minter has a gas balance.
+func (e *Ethereum) CheckConfig(ctx context.Context) []types.ConfigCheck {
+	checks := []types.ConfigCheck{types.NewConfigCheck(e.name, "minter key", e.minterAddress, nil)}
+
+	rpcClient, err := e.dialAndCheckChainID(ctx, e.rpcURL)
+	checks = append(checks, types.NewConfigCheck(e.name, "rpc", e.rpcURL, err))
+
+	wsClient, err := e.dialAndCheckChainID(ctx, e.wsURL)
+	checks = append(checks, types.NewConfigCheck(e.name, "ws", e.wsURL, err))
+	if wsClient != nil {
+		wsClient.Close()
+	}
+
+	if rpcClient == nil {
+		err := errors.New("rpc endpoint unavailable")
+		return append(checks,
+			types.NewConfigCheck(e.name, "message transmitter", "", err),
+			types.NewConfigCheck(e.name, "minter balance", "", err),
+		)
+	}
+	defer rpcClient.Close()
+
+	err = nil
+	if !common.IsHexAddress(e.messageTransmitterAddress) {
+		err = fmt.Errorf("invalid address %q", e.messageTransmitterAddress)
+	} else if code, codeErr := rpcClient.CodeAt(ctx, common.HexToAddress(e.messageTransmitterAddress), nil); codeErr != nil {
+		err = fmt.Errorf("unable to query contract code: %w", codeErr)
+	} else if len(code) == 0 {
+		err = fmt.Errorf("no contract code at %s", e.messageTransmitterAddress)
+	}
+	checks = append(checks, types.NewConfigCheck(e.name, "message transmitter", e.messageTransmitterAddress, err))
+
+	var value string
+	balance, err := rpcClient.BalanceAt(ctx, common.HexToAddress(e.minterAddress), nil)
+	if err != nil {
+		err = fmt.Errorf("unable to query balance: %w", err)
+	} else {
+		value = fmt.Sprintf("%s wei", balance)
+		if balance.Sign() == 0 {
+			err = fmt.Errorf("minter %s has no gas balance", e.minterAddress)
+		}
+	}
+	checks = append(checks, types.NewConfigCheck(e.name, "minter balance", value, err))
+
+	return checks
+}
+
+// dialAndCheckChainID dials the endpoint and ensures it serves the configured chain id.
+// The client is returned as long as the dial succeeded.
+func (e *Ethereum) dialAndCheckChainID(ctx context.Context, url string) (*ethclient.Client, error) {
+	client, err := ethclient.DialContext(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial: %w", err)
+	}
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		return client, fmt.Errorf("unable to query chain id: %w", err)
+	}
+	if chainID.Int64() != e.chainID {
+		return client, fmt.Errorf("endpoint serves chain id %s, expected %d", chainID, e.chainID)
+	}
+	return client, nil
+}
+
 func (e *Ethereum) LastFlushedBlock() uint64 {
 	return e.lastFlushedBlock
 }
@@ -146,6 +327,61 @@ func (e *Ethereum) InitializeClients(ctx context.Context, logger log.Logger) err
 	return nil
 }
 
+func (e *Ethereum) TriggerFlush() {
+	select {
+	case e.flushTrigger <- struct{}{}:
+	default:
+	}
+}
+
+// QueryTxMessages fetches the receipt of a source tx and parses every MessageSent log
+// emitted by the MessageTransmitter contract.
+func (e *Ethereum) QueryTxMessages(ctx context.Context, logger log.Logger, txHash string) (*types.TxState, error) {
+	messageTransmitterABI, messageSent, err := parseMessageTransmitterABI()
+	if err != nil {
+		return nil, err
+	}
+	messageTransmitterAddress := common.HexToAddress(e.messageTransmitterAddress)
+
+	receipt, err := e.rpcClient.TransactionReceipt(ctx, common.HexToHash(txHash))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get receipt for tx %s: %w", txHash, err)
+	}
+
+	var txState *types.TxState
+	for _, log := range receipt.Logs {
+		if log.Address != messageTransmitterAddress || len(log.Topics) == 0 || log.Topics[0] != messageSent.ID {
+			continue
+		}
+		parsedMsg, err := types.EvmLogToMessageState(messageTransmitterABI, messageSent, log)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse log into MessageState: %w", err)
+		}
+		if txState == nil {
+			txState = &types.TxState{TxHash: parsedMsg.SourceTxHash}
+		}
+		txState.Msgs = append(txState.Msgs, parsedMsg)
+	}
+
+	if txState == nil {
+		return nil, fmt.Errorf("no CCTP messages found in tx %s", txHash)
+	}
+	return txState, nil
+}
+
+// parseMessageTransmitterABI returns the embedded MessageTransmitter abi and its MessageSent event.
+func parseMessageTransmitterABI() (abi.ABI, abi.Event, error) {
+	messageTransmitter, err := content.ReadFile("abi/MessageTransmitter.json")
+	if err != nil {
+		return abi.ABI{}, abi.Event{}, fmt.Errorf("unable to read MessageTransmitter abi: %w", err)
+	}
+	messageTransmitterABI, err := abi.JSON(bytes.NewReader(messageTransmitter))
+	if err != nil {
+		return abi.ABI{}, abi.Event{}, fmt.Errorf("unable to parse MessageTransmitter abi: %w", err)
+	}
+	return messageTransmitterABI, messageTransmitterABI.Events["MessageSent"], nil
+}
+
 func (e *Ethereum) CloseClients() error {
 	if e.wsClient != nil {
 		e.wsClient.Close()
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/strangelove-ventures/noble-cctp-relayer/types"
 )
@@ -11,6 +12,9 @@ import (
 var _ types.ChainConfig = (*ChainConfig)(nil)
 
 type ChainConfig struct {
+	// Preset references a built-in chain preset, ex: "mainnet/ethereum". Fields set in the config override the preset
+	Preset string `yaml:"preset,omitempty"`
+
 	RPC                string `yaml:"rpc"`
 	WS                 string `yaml:"ws"`
 	Domain             types.Domain
@@ -23,6 +27,34 @@ type ChainConfig struct {
 	BroadcastRetries       int `yaml:"broadcast-retries"`
 	BroadcastRetryInterval int `yaml:"broadcast-retry-interval"`
 
+	// Confirmations is how many blocks deep a mint tx must be before its messages are complete. Defaults to 1
+	Confirmations uint64 `yaml:"confirmations"`
+	// ReceiptTimeout is how long to wait for a mint tx to be confirmed before its messages are requeued, to wait on it again. Defaults to 5m
+	ReceiptTimeout time.Duration `yaml:"receipt-timeout"`
+	// BumpTimeout is how long a mint tx may go unmined before it is replaced with bumped fees. 0 disables replacement
+	BumpTimeout time.Duration `yaml:"bump-timeout"`
+	// MaxFeeGwei is the hard max fee per gas of mint txs, replacements included. Messages are held while the
+	// network fee is above it. Required with BumpTimeout
+	MaxFeeGwei uint64 `yaml:"max-fee-gwei"`
+
+	// FeeMode is "dynamic" for EIP-1559 fees or "legacy" for a gas price. Defaults to dynamic
+	FeeMode string `yaml:"fee-mode"`
+	// TipMultiplier scales the suggested priority fee. Defaults to 1
+	TipMultiplier float64 `yaml:"tip-multiplier"`
+	// FeeMultiplier scales the base fee in the max fee per gas, or the suggested gas price in legacy mode.
+	// Defaults to 2, or 1 in legacy mode
+	FeeMultiplier float64 `yaml:"fee-multiplier"`
+	// MaxMintCostGwei is the max expected cost of a single mint tx. Messages are held while a mint would cost more
+	MaxMintCostGwei uint64 `yaml:"max-mint-cost-gwei"`
+
+	// Multicall is the address of a Multicall3 contract. When set, messages without a destination caller are minted
+	// together in a single aggregate3 call
+	Multicall string `yaml:"multicall"`
+	// MulticallWindow is how long attested messages of different txs are collected to be minted in a multicall. Defaults to 2s
+	MulticallWindow time.Duration `yaml:"multicall-window"`
+	// MulticallMaxMsgs is the max number of messages in a multicall, it is minted without waiting for the window once full. Defaults to 50
+	MulticallMaxMsgs int `yaml:"multicall-max-msgs"`
+
 	MinMintAmount uint64 `yaml:"min-mint-amount"`
 
 	MetricsDenom    string `yaml:"metrics-denom"`
@@ -31,18 +63,32 @@ type ChainConfig struct {
 	MinterPrivateKey string `yaml:"minter-private-key"`
 }
 
+// ApplyPreset sets the chain's known values from the preset.
+func (c *ChainConfig) ApplyPreset(preset types.ChainPreset) {
+	c.Domain = preset.Domain
+	c.ChainID = preset.EVMChainID
+	c.MessageTransmitter = preset.MessageTransmitter
+	c.LookbackPeriod = preset.LookbackPeriod
+	c.MetricsDenom = preset.MetricsDenom
+	c.MetricsExponent = preset.MetricsExponent
+}
+
 func (c *ChainConfig) Chain(name string) (types.Chain, error) {
-	envKey := strings.ToUpper(name) + "_PRIV_KEY"
-	privKey := os.Getenv(envKey)
-
-	if len(c.MinterPrivateKey) == 0 || len(privKey) != 0 {
-		if len(privKey) == 0 {
-			return nil, fmt.Errorf("env variable %s is empty, priv key not found for chain %s", envKey, name)
-		} else {
-			c.MinterPrivateKey = privKey
-		}
+	privKey, _, err := c.minterPrivateKey(name)
+	if err != nil {
+		return nil, err
 	}
+	c.MinterPrivateKey = privKey
+
+	return c.newChain(name, c.MinterPrivateKey)
+}
+
+// QueryChain creates the chain without the minter private key.
+func (c *ChainConfig) QueryChain(name string) (types.Chain, error) {
+	return c.newChain(name, "")
+}
 
+func (c *ChainConfig) newChain(name string, privKey string) (types.Chain, error) {
 	return NewChain(
 		name,
 		c.Domain,
@@ -52,11 +98,48 @@ func (c *ChainConfig) Chain(name string) (types.Chain, error) {
 		c.MessageTransmitter,
 		c.StartBlock,
 		c.LookbackPeriod,
-		c.MinterPrivateKey,
+		privKey,
 		c.BroadcastRetries,
 		c.BroadcastRetryInterval,
+		c.Confirmations,
+		c.ReceiptTimeout,
+		c.BumpTimeout,
+		c.MaxFeeGwei,
+		c.FeeMode,
+		c.TipMultiplier,
+		c.FeeMultiplier,
+		c.MaxMintCostGwei,
+		c.Multicall,
+		c.MulticallWindow,
+		c.MulticallMaxMsgs,
 		c.MinMintAmount,
 		c.MetricsDenom,
 		c.MetricsExponent,
 	)
 }
+
+// MinterAddress resolves the minter private key the same way as Chain and returns its address
+// along with where the key was found.
+func (c *ChainConfig) MinterAddress(name string) (address string, source string, err error) {
+	privKey, source, err := c.minterPrivateKey(name)
+	if err != nil {
+		return "", "", err
+	}
+	_, address, err = GetEcdsaKeyAddress(privKey)
+	if err != nil {
+		return "", "", err
+	}
+	return address, source, nil
+}
+
+// minterPrivateKey returns the minter private key, preferring the <NAME>_PRIV_KEY env variable over the config.
+func (c *ChainConfig) minterPrivateKey(name string) (privKey string, source string, err error) {
+	envKey := strings.ToUpper(name) + "_PRIV_KEY"
+	if privKey := os.Getenv(envKey); len(privKey) != 0 {
+		return privKey, "env " + envKey, nil
+	}
+	if len(c.MinterPrivateKey) == 0 {
+		return "", "", fmt.Errorf("env variable %s is empty, priv key not found for chain %s", envKey, name)
+	}
+	return c.MinterPrivateKey, "config", nil
+}
@@ -0,0 +1,129 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	// FeeModeDynamic signs EIP-1559 txs with a max fee and priority fee
+	FeeModeDynamic = "dynamic"
+	// FeeModeLegacy signs legacy txs with a gas price
+	FeeModeLegacy = "legacy"
+
+	defaultTipMultiplier       = 1
+	defaultFeeMultiplier       = 2
+	defaultLegacyFeeMultiplier = 1
+)
+
+// txFees are the fees of a mint tx. gasPrice is set for legacy txs, feeCap and tipCap for EIP-1559 txs.
+type txFees struct {
+	gasPrice *big.Int
+	feeCap   *big.Int
+	tipCap   *big.Int
+
+	// networkFee is the price per gas the tx is expected to pay
+	networkFee *big.Int
+}
+
+// apply sets the fees on the transactor
+func (f *txFees) apply(auth *bind.TransactOpts) {
+	auth.GasPrice, auth.GasFeeCap, auth.GasTipCap = f.gasPrice, f.feeCap, f.tipCap
+}
+
+// fees returns the fees of a mint tx from the current network fees and the chain's fee policy.
+// An error wrapping types.ErrFeeCapExceeded is returned if the network fee is above max-fee-gwei.
+func (e *Ethereum) fees(ctx context.Context) (*txFees, error) {
+	head, err := e.rpcClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query latest header: %w", err)
+	}
+
+	// chains without a base fee only accept legacy txs
+	if e.feeMode == FeeModeLegacy || head.BaseFee == nil {
+		suggested, err := e.rpcClient.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to query gas price: %w", err)
+		}
+		if err := e.checkFeeCap(suggested); err != nil {
+			return nil, err
+		}
+		multiplier := e.feeMultiplier
+		if multiplier == 0 {
+			multiplier = defaultLegacyFeeMultiplier
+		}
+		gasPrice := e.capFee(mulFloat(suggested, multiplier))
+		return &txFees{gasPrice: gasPrice, networkFee: gasPrice}, nil
+	}
+
+	suggestedTip, err := e.rpcClient.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query priority fee: %w", err)
+	}
+	tipCap := mulFloat(suggestedTip, e.tipMultiplier)
+
+	// the base fee and priority fee are the least a tx pays per gas to be included
+	networkFee := new(big.Int).Add(head.BaseFee, tipCap)
+	if err := e.checkFeeCap(networkFee); err != nil {
+		return nil, err
+	}
+
+	multiplier := e.feeMultiplier
+	if multiplier == 0 {
+		multiplier = defaultFeeMultiplier
+	}
+	feeCap := e.capFee(new(big.Int).Add(mulFloat(head.BaseFee, multiplier), tipCap))
+	return &txFees{feeCap: feeCap, tipCap: tipCap, networkFee: networkFee}, nil
+}
+
+// CheckFees queries the network fee and checks it against max-fee-gwei.
+func (e *Ethereum) CheckFees(ctx context.Context) error {
+	_, err := e.fees(ctx)
+	return err
+}
+
+// checkFeeCap returns an error wrapping types.ErrFeeCapExceeded if the network fee is above max-fee-gwei.
+func (e *Ethereum) checkFeeCap(networkFee *big.Int) error {
+	if e.maxFeeCap.Sign() > 0 && networkFee.Cmp(e.maxFeeCap) > 0 {
+		return fmt.Errorf("%w: network fee is %s gwei, max-fee-gwei is %s", types.ErrFeeCapExceeded, gwei(networkFee), gwei(e.maxFeeCap))
+	}
+	return nil
+}
+
+// checkMintCost returns an error wrapping types.ErrFeeCapExceeded if a mint using the gas is expected to cost more
+// than max-mint-cost-gwei.
+func (e *Ethereum) checkMintCost(gas uint64, fees *txFees) error {
+	if e.maxMintCost.Sign() == 0 {
+		return nil
+	}
+	cost := new(big.Int).Mul(new(big.Int).SetUint64(gas), fees.networkFee)
+	if cost.Cmp(e.maxMintCost) > 0 {
+		return fmt.Errorf("%w: mint would cost %s gwei, max-mint-cost-gwei is %s", types.ErrFeeCapExceeded, gwei(cost), gwei(e.maxMintCost))
+	}
+	return nil
+}
+
+// capFee caps a fee per gas at max-fee-gwei, if set
+func (e *Ethereum) capFee(fee *big.Int) *big.Int {
+	if e.maxFeeCap.Sign() > 0 && fee.Cmp(e.maxFeeCap) > 0 {
+		return new(big.Int).Set(e.maxFeeCap)
+	}
+	return fee
+}
+
+// mulFloat multiplies an amount of wei by a float, rounding down
+func mulFloat(x *big.Int, f float64) *big.Int {
+	product, _ := new(big.Float).Mul(new(big.Float).SetInt(x), big.NewFloat(f)).Int(nil)
+	return product
+}
+
+// gwei formats an amount of wei in gwei
+func gwei(wei *big.Int) string {
+	return new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(params.GWei)).Text('f', 2)
+}
@@ -0,0 +1,105 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+func TestMulFloat(t *testing.T) {
+	tests := []struct {
+		name string
+		x    int64
+		f    float64
+		want int64
+	}{
+		{"identity", 100, 1, 100},
+		{"double", 100, 2, 200},
+		{"fraction", 100, 1.5, 150},
+		{"rounds down", 7, 1.5, 10},
+		{"zero", 100, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, big.NewInt(tt.want), mulFloat(big.NewInt(tt.x), tt.f))
+		})
+	}
+
+	// wei amounts exceed the precision of a float64
+	x, _ := new(big.Int).SetString("123456789012345678901", 10)
+	require.Equal(t, x, mulFloat(x, 1))
+}
+
+func TestCapFee(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxFeeCap int64
+		fee       int64
+		want      int64
+	}{
+		{"no cap", 0, 500, 500},
+		{"below cap", 100, 50, 50},
+		{"at cap", 100, 100, 100},
+		{"above cap", 100, 150, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Ethereum{maxFeeCap: big.NewInt(tt.maxFeeCap)}
+			require.Equal(t, big.NewInt(tt.want), e.capFee(big.NewInt(tt.fee)))
+		})
+	}
+}
+
+func TestCheckFeeCap(t *testing.T) {
+	tests := []struct {
+		name       string
+		maxFeeCap  int64
+		networkFee int64
+		exceeded   bool
+	}{
+		{"no cap", 0, 500, false},
+		{"below cap", 100, 50, false},
+		{"at cap", 100, 100, false},
+		{"above cap", 100, 101, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Ethereum{maxFeeCap: big.NewInt(tt.maxFeeCap)}
+			err := e.checkFeeCap(big.NewInt(tt.networkFee))
+			if tt.exceeded {
+				require.ErrorIs(t, err, types.ErrFeeCapExceeded)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckMintCost(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxMintCost int64
+		gas         uint64
+		networkFee  int64
+		exceeded    bool
+	}{
+		{"no max", 0, 1_000_000, 1_000, false},
+		{"below max", 1_000, 10, 50, false},
+		{"at max", 1_000, 10, 100, false},
+		{"above max", 1_000, 11, 100, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &Ethereum{maxMintCost: big.NewInt(tt.maxMintCost)}
+			err := e.checkMintCost(tt.gas, &txFees{networkFee: big.NewInt(tt.networkFee)})
+			if tt.exceeded {
+				require.ErrorIs(t, err, types.ErrFeeCapExceeded)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package ethereum
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"math/big"
@@ -28,28 +27,25 @@ type errSignal struct {
 // StartListener starts the ethereum websocket subscription, queries history pertaining to the lookback period,
 // and starts the reoccurring flush
 //
+// If no start block is configured, history is queried from the chain's checkpoint instead of the latest block.
+//
 // If an error occurs in websocket stream, this function will handle relevant sub routines and then re-run itself.
 func (e *Ethereum) StartListener(
 	ctx context.Context,
 	logger log.Logger,
 	processingQueue chan *types.TxState,
+	checkpoints *types.Checkpoints,
 	flushOnlyMode bool,
 	flushInterval time.Duration,
 ) {
 	logger = logger.With("chain", e.name, "chain_id", e.chainID, "domain", e.domain)
 
-	messageTransmitter, err := content.ReadFile("abi/MessageTransmitter.json")
-	if err != nil {
-		logger.Error("Unable to read MessageTransmitter abi", "err", err)
-		os.Exit(1)
-	}
-	messageTransmitterABI, err := abi.JSON(bytes.NewReader(messageTransmitter))
+	messageTransmitterABI, messageSent, err := parseMessageTransmitterABI()
 	if err != nil {
-		logger.Error("Unable to parse MessageTransmitter abi", "err", err)
+		logger.Error("Unable to load MessageTransmitter abi", "err", err)
 		os.Exit(1)
 	}
 
-	messageSent := messageTransmitterABI.Events["MessageSent"]
 	messageTransmitterAddress := common.HexToAddress(e.messageTransmitterAddress)
 
 	sig := &errSignal{
@@ -58,29 +54,37 @@ func (e *Ethereum) StartListener(
 
 	// FlushOnlyMode is used for the secondary, flush only relayer. When enabled, the main stream is not started.
 	if flushOnlyMode {
-		go e.flushMechanism(ctx, logger, processingQueue, messageSent, messageTransmitterAddress, messageTransmitterABI, flushOnlyMode, flushInterval, sig)
+		go e.flushMechanism(ctx, logger, processingQueue, checkpoints, messageSent, messageTransmitterAddress, messageTransmitterABI, flushOnlyMode, flushInterval, sig)
 	} else {
 		// start main stream (does not account for lookback period or specific start block)
 		stream, sub, history := e.startMainStream(ctx, logger, messageSent, messageTransmitterAddress)
+		e.setStreamAlive(true)
 
-		go e.consumeStream(ctx, logger, processingQueue, messageSent, messageTransmitterABI, stream, sig)
-		consumeHistory(logger, history, processingQueue, messageSent, messageTransmitterABI)
+		// the stream only advances the checkpoint once the history before it was scanned
+		historyDone := make(chan struct{})
+		go e.consumeStream(ctx, logger, processingQueue, checkpoints, messageSent, messageTransmitterABI, stream, historyDone, sig)
+		e.consumeHistory(logger, history, processingQueue, checkpoints, messageSent, messageTransmitterABI)
 
 		// get history from (start block - lookback) up until latest block
 		latestBlock := e.LatestBlock()
 		start := latestBlock
 		if e.startBlock != 0 {
 			start = e.startBlock
+		} else if checkpoint, ok := checkpoints.Load(e.name); ok {
+			logger.Info(fmt.Sprintf("Resuming from checkpoint at block %d", checkpoint))
+			start = checkpoint
 		}
 		startLookback := start - e.lookbackPeriod
+		checkpoints.Set(e.name, startLookback)
 
 		logger.Info(fmt.Sprintf("Getting history from %d: starting at: %d looking back %d blocks", startLookback, start, e.lookbackPeriod))
-		e.getAndConsumeHistory(ctx, logger, processingQueue, messageSent, messageTransmitterAddress, messageTransmitterABI, startLookback, latestBlock)
+		e.getAndConsumeHistory(ctx, logger, processingQueue, checkpoints, messageSent, messageTransmitterAddress, messageTransmitterABI, startLookback, latestBlock)
+		checkpoints.Advance(e.name, latestBlock)
+		close(historyDone)
 		logger.Info("Finished getting history")
 
-		if flushInterval > 0 {
-			go e.flushMechanism(ctx, logger, processingQueue, messageSent, messageTransmitterAddress, messageTransmitterABI, flushOnlyMode, flushInterval, sig)
-		}
+		// the flush mechanism also serves manually triggered flushes when no flush interval is set
+		go e.flushMechanism(ctx, logger, processingQueue, checkpoints, messageSent, messageTransmitterAddress, messageTransmitterABI, flushOnlyMode, flushInterval, sig)
 
 		// listen for errors in the main websocket stream
 		// if error occurs, trigger sig.Ready
@@ -90,12 +94,13 @@ func (e *Ethereum) StartListener(
 			return
 		case err := <-sub.Err():
 			logger.Error("Websocket disconnected. Reconnecting...", "err", err)
+			e.setStreamAlive(false)
 			close(sig.Ready)
 
 			// restart
 			e.startBlock = e.lastFlushedBlock
 			time.Sleep(10 * time.Millisecond)
-			e.StartListener(ctx, logger, processingQueue, flushOnlyMode, flushInterval)
+			e.StartListener(ctx, logger, processingQueue, checkpoints, flushOnlyMode, flushInterval)
 			return
 		}
 	}
@@ -139,10 +144,13 @@ func (e *Ethereum) startMainStream(
 	return stream, sub, history
 }
 
+// getAndConsumeHistory queries the MessageSent logs from start to end in chunks and places them on the processing queue.
+// If checkpoints is set, each tx is held in it until the processor persists it.
 func (e *Ethereum) getAndConsumeHistory(
 	ctx context.Context,
 	logger log.Logger,
 	processingQueue chan *types.TxState,
+	checkpoints *types.Checkpoints,
 	messageSent abi.Event,
 	messageTransmitterAddress common.Address,
 	messageTransmitterABI abi.ABI,
@@ -159,11 +167,12 @@ func (e *Ethereum) getAndConsumeHistory(
 	// handle historical queries in chunks (some websockets only allow small history queries)
 	const chunkSize = uint64(100)
 	chunk := 1
-	totalChunksNeeded := (end - start + chunkSize - 1) / chunkSize
+	totalChunksNeeded := (end - start + chunkSize) / chunkSize
 
-	for start < end {
+	// both bounds of a filter query are inclusive
+	for start <= end {
 		fromBlock := start
-		toBlock := start + chunkSize
+		toBlock := start + chunkSize - 1
 		if toBlock > end {
 			toBlock = end
 		}
@@ -182,6 +191,9 @@ func (e *Ethereum) getAndConsumeHistory(
 		for {
 			_, toUnSub, history, err = etherReader.QueryWithHistory(ctx, &query)
 			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
 				// TODO: add metrics for this log
 				logger.Error(fmt.Sprintf("Unable to query history from %d to %d. attempt: %d", start, end, queryAttempt), "err", err)
 				queryAttempt++
@@ -191,19 +203,41 @@ func (e *Ethereum) getAndConsumeHistory(
 			break
 		}
 		toUnSub.Unsubscribe()
-		consumeHistory(logger, history, processingQueue, messageSent, messageTransmitterABI)
+		e.consumeHistory(logger, history, processingQueue, checkpoints, messageSent, messageTransmitterABI)
 
 		start += chunkSize
 		chunk++
 	}
 }
 
+// ConsumeHistory queries the MessageSent logs from start to end in chunks and places them on the processing queue.
+func (e *Ethereum) ConsumeHistory(
+	ctx context.Context,
+	logger log.Logger,
+	processingQueue chan *types.TxState,
+	start, end uint64,
+) error {
+	messageTransmitterABI, messageSent, err := parseMessageTransmitterABI()
+	if err != nil {
+		return fmt.Errorf("unable to load MessageTransmitter abi: %w", err)
+	}
+	if start > end {
+		return fmt.Errorf("start block %d is greater than end block %d", start, end)
+	}
+
+	logger = logger.With("chain", e.name, "chain_id", e.chainID, "domain", e.domain)
+	// the txs are not relayed by the listener's processor, so they do not hold back its checkpoint
+	e.getAndConsumeHistory(ctx, logger, processingQueue, nil, messageSent, common.HexToAddress(e.messageTransmitterAddress), messageTransmitterABI, start, end)
+	return ctx.Err()
+}
+
 // consumeHistory consumes the history from a QueryWithHistory() go-ethereum call.
-// it passes messages to the processingQueue
-func consumeHistory(
+// it passes messages to the processingQueue, holding them in checkpoints if set
+func (e *Ethereum) consumeHistory(
 	logger log.Logger,
 	history []ethtypes.Log,
 	processingQueue chan *types.TxState,
+	checkpoints *types.Checkpoints,
 	messageSent abi.Event,
 	messageTransmitterABI abi.ABI,
 ) {
@@ -216,19 +250,27 @@ func consumeHistory(
 		}
 		logger.Info(fmt.Sprintf("New historical msg from source domain %d with tx hash %s", parsedMsg.SourceDomain, parsedMsg.SourceTxHash))
 
+		if checkpoints != nil {
+			checkpoints.Hold(e.name, parsedMsg.SourceTxHash, historicalLog.BlockNumber)
+		}
 		processingQueue <- &types.TxState{TxHash: parsedMsg.SourceTxHash, Msgs: []*types.MessageState{parsedMsg}}
 	}
 }
 
 // consumeStream consumes incoming transactions from a QueryWithHistory() go-ethereum call.
 // if the websocket is disconnect, it restarts the stream using the last seen block height as the start height.
+//
+// Logs arrive in block order, so once a log for block N is seen, every block before N has been passed to the processing queue.
+// Until historyDone is closed, blocks before the stream may not be scanned yet, so the checkpoint is left to the history scan.
 func (e *Ethereum) consumeStream(
 	ctx context.Context,
 	logger log.Logger,
 	processingQueue chan *types.TxState,
+	checkpoints *types.Checkpoints,
 	messageSent abi.Event,
 	messageTransmitterABI abi.ABI,
 	stream <-chan ethtypes.Log,
+	historyDone <-chan struct{},
 	sig *errSignal,
 
 ) {
@@ -249,6 +291,9 @@ func (e *Ethereum) consumeStream(
 			}
 			logger.Info(fmt.Sprintf("New stream msg from %d with tx hash %s", parsedMsg.SourceDomain, parsedMsg.SourceTxHash))
 
+			// the tx holds the checkpoint until the processor persists it
+			checkpoints.Hold(e.name, parsedMsg.SourceTxHash, streamLog.BlockNumber)
+
 			switch {
 			case txState == nil:
 				txState = &types.TxState{TxHash: parsedMsg.SourceTxHash, Msgs: []*types.MessageState{parsedMsg}}
@@ -258,6 +303,15 @@ func (e *Ethereum) consumeStream(
 			default:
 				txState.Msgs = append(txState.Msgs, parsedMsg)
 			}
+
+			if streamLog.BlockNumber > 0 {
+				select {
+				case <-historyDone:
+					checkpoints.Advance(e.name, streamLog.BlockNumber-1)
+				default:
+					// the blocks before the stream log may not be scanned yet
+				}
+			}
 		default:
 			if txState != nil {
 				processingQueue <- txState
@@ -281,6 +335,7 @@ func (e *Ethereum) flushMechanism(
 	ctx context.Context,
 	logger log.Logger,
 	processingQueue chan *types.TxState,
+	checkpoints *types.Checkpoints,
 	messageSent abi.Event,
 	messageTransmitterAddress common.Address,
 	messageTransmitterABI abi.ABI,
@@ -288,7 +343,11 @@ func (e *Ethereum) flushMechanism(
 	flushInterval time.Duration,
 	sig *errSignal,
 ) {
-	logger.Info(fmt.Sprintf("Starting flush mechanism. Will flush every %v", flushInterval))
+	if flushInterval > 0 {
+		logger.Info(fmt.Sprintf("Starting flush mechanism. Will flush every %v", flushInterval))
+	} else {
+		logger.Info("Starting flush mechanism. Will only flush when triggered")
+	}
 
 	// extraFlushBlocks is used to add an extra space between latest height and last flushed block
 	// this setting should only be used for the secondary, flush only relayer
@@ -298,51 +357,75 @@ func (e *Ethereum) flushMechanism(
 	}
 
 	for {
+		// without a flush interval, the timer never fires and only triggered flushes run
+		var timerC <-chan time.Time
 		timer := time.NewTimer(flushInterval)
+		if flushInterval > 0 {
+			timerC = timer.C
+		}
 		select {
-		case <-timer.C:
-			latestBlock := e.LatestBlock()
+		case <-timerC:
+		case <-e.flushTrigger:
+			timer.Stop()
+			logger.Info("Flush triggered")
+
+		// if main websocket stream is disconnected, stop flush. It will be restarted once websocket is reconnected
+		case <-sig.Ready:
+			timer.Stop()
+			logger.Debug("Websocket disconnected... Flush stopped. Will restart after websocket is re-established")
+			return
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		latestBlock := e.LatestBlock()
 
-			// initialize first lastFlushedBlock if not set
-			if e.lastFlushedBlock == 0 {
-				e.lastFlushedBlock = latestBlock - (2*e.lookbackPeriod + extraFlushBlocks)
+		// initialize first lastFlushedBlock if not set
+		if e.lastFlushedBlock == 0 {
+			e.lastFlushedBlock = latestBlock - (2*e.lookbackPeriod + extraFlushBlocks)
 
-				if latestBlock < e.lookbackPeriod {
-					e.lastFlushedBlock = 0
-				}
+			if latestBlock < e.lookbackPeriod {
+				e.lastFlushedBlock = 0
 			}
+		}
 
-			// start from the last block it flushed
-			startBlock := e.lastFlushedBlock
+		// start from the last block it flushed
+		startBlock := e.lastFlushedBlock
 
-			// set finish block to be latestBlock - lookbackPeriod
-			finishBlock := latestBlock - (e.lookbackPeriod + extraFlushBlocks)
+		// set finish block to be latestBlock - lookbackPeriod
+		finishBlock := latestBlock - (e.lookbackPeriod + extraFlushBlocks)
 
-			if startBlock >= finishBlock {
-				logger.Debug("No new blocks to flush")
-				continue
-			}
+		if startBlock >= finishBlock {
+			logger.Debug("No new blocks to flush")
+			continue
+		}
 
-			logger.Info(fmt.Sprintf("Flush started from %d to %d (current height: %d, lookback period: %d)", startBlock, finishBlock, latestBlock, e.lookbackPeriod))
+		logger.Info(fmt.Sprintf("Flush started from %d to %d (current height: %d, lookback period: %d)", startBlock, finishBlock, latestBlock, e.lookbackPeriod))
 
-			// consume from lastFlushedBlock to the finishBlock
-			e.getAndConsumeHistory(ctx, logger, processingQueue, messageSent, messageTransmitterAddress, messageTransmitterABI, startBlock, finishBlock)
+		// consume from lastFlushedBlock to the finishBlock
+		e.getAndConsumeHistory(ctx, logger, processingQueue, checkpoints, messageSent, messageTransmitterAddress, messageTransmitterABI, startBlock, finishBlock)
 
-			// update lastFlushedBlock to the last block it flushed
-			e.lastFlushedBlock = finishBlock
+		// update lastFlushedBlock to the last block it flushed
+		e.lastFlushedBlock = finishBlock
+		checkpoints.Advance(e.name, finishBlock)
 
-			logger.Info("Flush complete")
+		logger.Info("Flush complete")
+	}
+}
 
-		// if main websocket stream is disconnected, stop flush. It will be restarted once websocket is reconnected
-		case <-sig.Ready:
-			timer.Stop()
-			logger.Debug("Websocket disconnected... Flush stopped. Will restart after websocket is re-established")
-			return
-		case <-ctx.Done():
-			timer.Stop()
-			return
-		}
+// QueryLatestBlock queries the latest block number from the rpc endpoint.
+func (e *Ethereum) QueryLatestBlock(ctx context.Context) (uint64, error) {
+	return e.rpcClient.BlockNumber(ctx)
+}
+
+// BlockTime queries the timestamp of the block header at the height.
+func (e *Ethereum) BlockTime(ctx context.Context, height uint64) (time.Time, error) {
+	header, err := e.rpcClient.HeaderByNumber(ctx, new(big.Int).SetUint64(height))
+	if err != nil {
+		return time.Time{}, err
 	}
+	return time.Unix(int64(header.Time), 0), nil
 }
 
 func (e *Ethereum) TrackLatestBlockHeight(ctx context.Context, logger log.Logger, m *relayer.PromMetrics) {
@@ -353,7 +436,7 @@ func (e *Ethereum) TrackLatestBlockHeight(ctx context.Context, logger log.Logger
 	// helper function to query latest height and set metric
 	queryHeightAndSetMetric := func() {
 		// first time
-		res, err := e.rpcClient.BlockNumber(ctx)
+		res, err := e.QueryLatestBlock(ctx)
 		if err != nil {
 			logger.Error("Unable to query latest height", "err", err)
 		} else {
@@ -0,0 +1,345 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum/contracts"
+	"github.com/strangelove-ventures/noble-cctp-relayer/relayer"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	defaultMulticallWindow  = 2 * time.Second
+	defaultMulticallMaxMsgs = 50
+)
+
+// multicall3ABI is the aggregate3 function of the Multicall3 contract
+const multicall3ABI = `[{"inputs":[{"components":[{"internalType":"address","name":"target","type":"address"},{"internalType":"bool","name":"allowFailure","type":"bool"},{"internalType":"bytes","name":"callData","type":"bytes"}],"internalType":"struct Multicall3.Call3[]","name":"calls","type":"tuple[]"}],"name":"aggregate3","outputs":[{"components":[{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"internalType":"struct Multicall3.Result[]","name":"returnData","type":"tuple[]"}],"stateMutability":"payable","type":"function"}]`
+
+// call3 is a Multicall3 Call3 struct
+type call3 struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicallResult is a Multicall3 Result struct
+type multicallResult struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// submitMulticall submits the msgs that can be received through the multicall contract to be minted together with
+// the msgs of concurrent broadcasts, in a single aggregate3 call. It returns once their batch was minted, or with the
+// error of ctx once it is done, in which case the batch is still minted.
+func (e *Ethereum) submitMulticall(
+	ctx context.Context,
+	logger log.Logger,
+	msgs []*types.MessageState,
+	sequenceMap *types.SequenceMap,
+) error {
+	var eligible []*types.MessageState
+	for _, msg := range msgs {
+		if e.multicallEligible(msg) {
+			eligible = append(eligible, msg)
+		}
+	}
+	if len(eligible) == 0 {
+		return nil
+	}
+
+	e.multicallOnce.Do(func() {
+		// a batch holds the msgs of many broadcasts, so it is not cancelled with the broadcast that started it
+		e.multicallBatcher = relayer.NewBatcher(context.WithoutCancel(ctx), e.multicallWindow, e.multicallMaxMsgs, func(ctx context.Context, batch []*types.MessageState) error {
+			return e.broadcastMulticall(ctx, logger, batch, sequenceMap)
+		})
+	})
+	return e.multicallBatcher.Submit(ctx, eligible...)
+}
+
+// multicallEligible reports whether the msg can be minted through the multicall contract. The multicall contract is
+// the caller, so only msgs without a destination caller can be received through it. Msgs with a pending tx are not
+// minted again.
+func (e *Ethereum) multicallEligible(msg *types.MessageState) bool {
+	return msg.Status != types.Complete &&
+		bytes.Equal(msg.DestinationCaller, make([]byte, 32)) &&
+		e.pending.find(msg) == nil
+}
+
+// broadcastMulticall mints the msgs together, in a single aggregate3 call of the multicall contract. Only msgs whose
+// call succeeds in a simulation of the aggregate3 call are included. Msgs that are not complete afterwards are left
+// to be broadcast individually, unless the multicall tx is still pending.
+func (e *Ethereum) broadcastMulticall(
+	ctx context.Context,
+	logger log.Logger,
+	msgs []*types.MessageState,
+	sequenceMap *types.SequenceMap,
+) error {
+	transmitterABI, err := contracts.MessageTransmitterMetaData.GetAbi()
+	if err != nil {
+		return fmt.Errorf("unable to parse MessageTransmitter abi: %w", err)
+	}
+
+	var batch []*types.MessageState
+	var calls []call3
+	for _, msg := range msgs {
+		if !e.multicallEligible(msg) {
+			continue
+		}
+		attestationBytes, err := hex.DecodeString(strings.TrimPrefix(msg.Attestation, "0x"))
+		if err != nil {
+			continue
+		}
+		data, err := transmitterABI.Pack("receiveMessage", msg.MsgSentBytes, attestationBytes)
+		if err != nil {
+			return fmt.Errorf("unable to pack receiveMessage: %w", err)
+		}
+		batch = append(batch, msg)
+		calls = append(calls, call3{Target: common.HexToAddress(e.messageTransmitterAddress), AllowFailure: true, CallData: data})
+	}
+	if len(calls) < 2 {
+		return nil
+	}
+
+	auth, err := bind.NewKeyedTransactorWithChainID(e.privateKey, big.NewInt(e.chainID))
+	if err != nil {
+		return fmt.Errorf("unable to create auth: %w", err)
+	}
+	auth.Context = ctx
+
+	// calls that would revert are left out, they are broadcast individually to handle their revert
+	results, err := e.simulateMulticall(ctx, auth.From, calls)
+	if err != nil {
+		return err
+	}
+	var included []*types.MessageState
+	var includedCalls []call3
+	for i, result := range results {
+		if !result.Success {
+			logger.Debug(fmt.Sprintf("Leaving %s out of the multicall, its call reverts: %s", batch[i].SourceTxHash, multicallRevertReason(result.ReturnData)))
+			continue
+		}
+		included = append(included, batch[i])
+		includedCalls = append(includedCalls, calls[i])
+	}
+	if len(includedCalls) < 2 {
+		return nil
+	}
+
+	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return fmt.Errorf("unable to parse multicall abi: %w", err)
+	}
+	data, err := multicallABI.Pack("aggregate3", includedCalls)
+	if err != nil {
+		return fmt.Errorf("unable to pack aggregate3: %w", err)
+	}
+	multicallAddress := common.HexToAddress(e.multicallAddress)
+	gas, err := e.rpcClient.EstimateGas(ctx, ethereum.CallMsg{From: auth.From, To: &multicallAddress, Data: data})
+	if err != nil {
+		return fmt.Errorf("unable to estimate multicall gas: %w", err)
+	}
+
+	fees, err := e.fees(ctx)
+	if err != nil {
+		return err
+	}
+	fees.apply(auth)
+	auth.GasLimit = gas
+
+	nonce := sequenceMap.Next(e.domain)
+	auth.Nonce = new(big.Int).SetUint64(nonce)
+	auth.NoSend = true
+
+	multicall := bind.NewBoundContract(multicallAddress, multicallABI, e.rpcClient, e.rpcClient, e.rpcClient)
+	tx, err := multicall.Transact(auth, "aggregate3", includedCalls)
+	if err == nil {
+		// the max mint cost applies to each msg of the multicall
+		if err := e.checkMintCost(tx.Gas()/uint64(len(included)), fees); err != nil {
+			sequenceMap.Release(e.domain, nonce)
+			return err
+		}
+		err = e.rpcClient.SendTransaction(ctx, tx)
+	}
+	if err != nil {
+		e.handleSendError(ctx, logger, sequenceMap, nonce, err)
+		return fmt.Errorf("unable to broadcast multicall: %w", err)
+	}
+
+	for _, msg := range included {
+		msg.DestTxHash = tx.Hash().Hex()
+	}
+	logger.Info(fmt.Sprintf("Successfully broadcast %d messages to %s in multicall tx %s. Waiting for %d confirmations", len(included), e.name, tx.Hash().Hex(), e.confirmations))
+
+	return e.trackMulticallReceipt(ctx, logger, included, sequenceMap, tx)
+}
+
+// simulateMulticall simulates the aggregate3 call against the pending state and returns the result of each call.
+func (e *Ethereum) simulateMulticall(ctx context.Context, from common.Address, calls []call3) ([]multicallResult, error) {
+	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse multicall abi: %w", err)
+	}
+	data, err := multicallABI.Pack("aggregate3", calls)
+	if err != nil {
+		return nil, fmt.Errorf("unable to pack aggregate3: %w", err)
+	}
+
+	multicallAddress := common.HexToAddress(e.multicallAddress)
+	res, err := e.rpcClient.PendingCallContract(ctx, ethereum.CallMsg{From: from, To: &multicallAddress, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("unable to simulate multicall: %w", callError(err))
+	}
+
+	results, err := unpackMulticallResults(multicallABI, res)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("multicall returned %d results for %d calls", len(results), len(calls))
+	}
+	return results, nil
+}
+
+// unpackMulticallResults decodes the return data of an aggregate3 call
+func unpackMulticallResults(multicallABI abi.ABI, data []byte) ([]multicallResult, error) {
+	out, err := multicallABI.Unpack("aggregate3", data)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unpack aggregate3 result: %w", err)
+	}
+	if len(out) != 1 {
+		return nil, fmt.Errorf("unexpected aggregate3 result length %d", len(out))
+	}
+	return *abi.ConvertType(out[0], new([]multicallResult)).(*[]multicallResult), nil
+}
+
+// trackMulticallReceipt tracks the multicall tx and waits until it is confirmed, then records its result on each of
+// its msgs. If the tx is not confirmed within the receipt timeout, errTxPending is returned and the tx stays tracked.
+func (e *Ethereum) trackMulticallReceipt(
+	ctx context.Context,
+	logger log.Logger,
+	msgs []*types.MessageState,
+	sequenceMap *types.SequenceMap,
+	tx *ethtypes.Transaction,
+) error {
+	p := e.pending.track(msgs, tx)
+
+	receipt, err := e.waitForReceipt(ctx, logger, p)
+	if errors.Is(err, errTxDropped) {
+		for _, msg := range msgs {
+			msg.DestTxHash = ""
+		}
+		if e.pending.remove(p) {
+			sequenceMap.Release(e.domain, p.latest().Nonce())
+		}
+	}
+	if err != nil {
+		return err
+	}
+	e.pending.remove(p)
+
+	var minted int
+	for _, msg := range msgs {
+		if err := e.recordMulticallReceipt(msg, p, receipt); err != nil {
+			return err
+		}
+		if msg.Status == types.Complete {
+			minted++
+		}
+	}
+
+	logger.Info(fmt.Sprintf("Multicall tx %s confirmed in block %d. Minted %d of %d messages. Gas used: %d", receipt.TxHash.Hex(), receipt.BlockNumber, minted, len(msgs), receipt.GasUsed))
+	return nil
+}
+
+// recordMulticallReceipt records the result of a confirmed multicall tx on one of its msgs. The aggregate3 call
+// succeeds even if some of its calls failed, so the msg is only complete if the receipt holds the MessageReceived
+// event the multicall emitted for it. Otherwise its DestTxHash is cleared, so that it is minted on its own.
+func (e *Ethereum) recordMulticallReceipt(
+	msg *types.MessageState,
+	p *pendingTx,
+	receipt *ethtypes.Receipt,
+) error {
+	if p.isCancel(receipt.TxHash) {
+		// the msgs were received by other txs, the cancel only freed the account nonce
+		msg.Status = types.Complete
+		msg.DestTxHash = ""
+		return nil
+	}
+
+	received := false
+	if receipt.Status == ethtypes.ReceiptStatusSuccessful {
+		var err error
+		received, err = e.multicallReceived(msg, receipt)
+		if err != nil {
+			return err
+		}
+	}
+	if !received {
+		msg.DestTxHash = ""
+		return nil
+	}
+
+	// the gas of the multicall is shared by its msgs
+	msg.GasUsed = receipt.GasUsed / uint64(len(p.msgs))
+	msg.EffectiveGasPrice = receipt.EffectiveGasPrice
+	msg.DestTxHash = receipt.TxHash.Hex()
+	msg.Status = types.Complete
+	return nil
+}
+
+// multicallReceived reports whether the receipt holds a MessageReceived event of the message transmitter for the
+// msg, emitted by a call of the multicall contract
+func (e *Ethereum) multicallReceived(msg *types.MessageState, receipt *ethtypes.Receipt) (bool, error) {
+	messageTransmitter := common.HexToAddress(e.messageTransmitterAddress)
+	filterer, err := contracts.NewMessageTransmitterFilterer(messageTransmitter, nil)
+	if err != nil {
+		return false, fmt.Errorf("unable to bind message transmitter: %w", err)
+	}
+	messageTransmitterABI, err := contracts.MessageTransmitterMetaData.GetAbi()
+	if err != nil {
+		return false, fmt.Errorf("unable to parse message transmitter abi: %w", err)
+	}
+	receivedID := messageTransmitterABI.Events["MessageReceived"].ID
+	multicall := common.HexToAddress(e.multicallAddress)
+
+	for _, l := range receipt.Logs {
+		if l.Address != messageTransmitter || len(l.Topics) == 0 || l.Topics[0] != receivedID {
+			continue
+		}
+		event, err := filterer.ParseMessageReceived(*l)
+		if err != nil {
+			return false, fmt.Errorf("unable to parse MessageReceived event: %w", err)
+		}
+		if event.Caller == multicall && event.SourceDomain == uint32(msg.SourceDomain) && event.Nonce == msg.Nonce {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// multicallRevertReason decodes the return data of a failed call
+func multicallRevertReason(returnData []byte) string {
+	if reason, err := abi.UnpackRevert(returnData); err == nil {
+		return reason
+	}
+	if name, ok := customError(returnData); ok {
+		return name
+	}
+	return "unknown"
+}
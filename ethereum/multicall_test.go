@@ -0,0 +1,109 @@
+package ethereum
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum/contracts"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+func TestUnpackMulticallResults(t *testing.T) {
+	multicallABI, err := abi.JSON(strings.NewReader(multicall3ABI))
+	require.NoError(t, err)
+
+	revertData, err := (abi.Arguments{{Type: abi.Type{T: abi.StringTy}}}).Pack("Nonce already used")
+	require.NoError(t, err)
+	// revert data is prefixed with the Error(string) selector
+	revertData = append([]byte{0x08, 0xc3, 0x79, 0xa0}, revertData...)
+
+	expected := []multicallResult{
+		{Success: true, ReturnData: []byte{0x01}},
+		{Success: false, ReturnData: revertData},
+	}
+	data, err := multicallABI.Methods["aggregate3"].Outputs.Pack(expected)
+	require.NoError(t, err)
+
+	results, err := unpackMulticallResults(multicallABI, data)
+	require.NoError(t, err)
+	require.Equal(t, expected, results)
+	require.Equal(t, "Nonce already used", multicallRevertReason(results[1].ReturnData))
+
+	_, err = unpackMulticallResults(multicallABI, []byte{0x01})
+	require.Error(t, err)
+}
+
+func TestRecordMulticallReceipt(t *testing.T) {
+	messageTransmitter := common.HexToAddress("0x7865fAfC2db2093669d92c0F33AeEF291086BEFD")
+	multicall := common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+	e := &Ethereum{
+		messageTransmitterAddress: messageTransmitter.Hex(),
+		multicallAddress:          multicall.Hex(),
+	}
+
+	messageTransmitterABI, err := contracts.MessageTransmitterMetaData.GetAbi()
+	require.NoError(t, err)
+	event := messageTransmitterABI.Events["MessageReceived"]
+	receivedLog := func(address, caller common.Address, sourceDomain uint32, nonce uint64) *ethtypes.Log {
+		data, err := event.Inputs.NonIndexed().Pack(sourceDomain, [32]byte{}, []byte{})
+		require.NoError(t, err)
+		return &ethtypes.Log{
+			Address: address,
+			Topics: []common.Hash{
+				event.ID,
+				common.BytesToHash(caller.Bytes()),
+				common.BigToHash(new(big.Int).SetUint64(nonce)),
+			},
+			Data: data,
+		}
+	}
+
+	minted := &types.MessageState{SourceDomain: 4, Nonce: 10, DestTxHash: "0x01"}
+	failed := &types.MessageState{SourceDomain: 4, Nonce: 11, DestTxHash: "0x01"}
+	// received by another relayer's tx, not by a call of the multicall
+	otherCaller := &types.MessageState{SourceDomain: 4, Nonce: 12, DestTxHash: "0x01"}
+	// same nonce as a received msg from another domain
+	otherDomain := &types.MessageState{SourceDomain: 0, Nonce: 10, DestTxHash: "0x01"}
+	msgs := []*types.MessageState{minted, failed, otherCaller, otherDomain}
+
+	tx := ethtypes.NewTx(&ethtypes.DynamicFeeTx{Nonce: 1})
+	p := &pendingTx{msgs: msgs, versions: []*ethtypes.Transaction{tx}}
+	receipt := &ethtypes.Receipt{
+		Status:            ethtypes.ReceiptStatusSuccessful,
+		TxHash:            tx.Hash(),
+		GasUsed:           400_000,
+		EffectiveGasPrice: big.NewInt(7),
+		Logs: []*ethtypes.Log{
+			receivedLog(messageTransmitter, multicall, 4, 10),
+			receivedLog(messageTransmitter, common.HexToAddress("0x01"), 4, 12),
+			// not emitted by the message transmitter
+			receivedLog(common.HexToAddress("0x02"), multicall, 4, 11),
+		},
+	}
+
+	for _, msg := range msgs {
+		require.NoError(t, e.recordMulticallReceipt(msg, p, receipt))
+	}
+
+	require.Equal(t, types.Complete, minted.Status)
+	require.Equal(t, tx.Hash().Hex(), minted.DestTxHash)
+	require.Equal(t, uint64(100_000), minted.GasUsed)
+	require.Equal(t, big.NewInt(7), minted.EffectiveGasPrice)
+	for _, msg := range []*types.MessageState{failed, otherCaller, otherDomain} {
+		require.NotEqual(t, types.Complete, msg.Status)
+		require.Empty(t, msg.DestTxHash)
+	}
+
+	// a reverted multicall completes none of its msgs
+	minted.Status, minted.DestTxHash = "", "0x01"
+	receipt.Status = ethtypes.ReceiptStatusFailed
+	require.NoError(t, e.recordMulticallReceipt(minted, p, receipt))
+	require.NotEqual(t, types.Complete, minted.Status)
+	require.Empty(t, minted.DestTxHash)
+}
@@ -0,0 +1,397 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	// replaceCheckInterval is how often the pending txs are checked for ones to replace
+	replaceCheckInterval = 10 * time.Second
+	// gapCheckInterval is how often released account nonces are checked for gaps to fill
+	gapCheckInterval = 30 * time.Second
+)
+
+// pendingTx is a submitted mint tx that is not confirmed yet. Every signed version of the tx
+// shares its account nonce, and any of them may be mined.
+type pendingTx struct {
+	// msgs are the messages the tx receives, more than one for a multicall
+	msgs []*types.MessageState
+
+	mu       sync.Mutex
+	versions []*ethtypes.Transaction
+	// cancel is the self-transfer replacing the tx, if its messages were received by other txs
+	cancel *ethtypes.Transaction
+	// submitted is when the latest version was sent
+	submitted time.Time
+}
+
+// multicall reports whether the tx receives its msgs through the multicall contract. Multicall txs receive at least
+// two msgs, other txs a single one.
+func (p *pendingTx) multicall() bool {
+	return len(p.msgs) > 1
+}
+
+// latest returns the most recently signed version of the tx
+func (p *pendingTx) latest() *ethtypes.Transaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.versions[len(p.versions)-1]
+}
+
+// signed returns every signed version of the tx
+func (p *pendingTx) signed() []*ethtypes.Transaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*ethtypes.Transaction(nil), p.versions...)
+}
+
+// version returns the signed version of the tx with the hash
+func (p *pendingTx) version(hash common.Hash) *ethtypes.Transaction {
+	for _, tx := range p.signed() {
+		if tx.Hash() == hash {
+			return tx
+		}
+	}
+	return p.latest()
+}
+
+// isCancel reports whether the tx with the hash is the self-transfer cancelling the tx
+func (p *pendingTx) isCancel(hash common.Hash) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.cancel != nil && p.cancel.Hash() == hash
+}
+
+// replaced records a new version of the tx and restarts its bump timeout
+func (p *pendingTx) replaced(tx *ethtypes.Transaction, cancel bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.versions = append(p.versions, tx)
+	if cancel {
+		p.cancel = tx
+	}
+	p.submitted = time.Now()
+}
+
+// stuck reports whether no version of the tx was sent within the timeout
+func (p *pendingTx) stuck(timeout time.Duration) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return time.Since(p.submitted) > timeout
+}
+
+// touch restarts the bump timeout of the tx
+func (p *pendingTx) touch() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.submitted = time.Now()
+}
+
+// pendingTxs tracks the minter's submitted txs by account nonce.
+type pendingTxs struct {
+	mu  sync.Mutex
+	txs map[uint64]*pendingTx
+}
+
+func newPendingTxs() *pendingTxs {
+	return &pendingTxs{txs: make(map[uint64]*pendingTx)}
+}
+
+// track starts tracking a submitted tx under its account nonce
+func (p *pendingTxs) track(msgs []*types.MessageState, tx *ethtypes.Transaction) *pendingTx {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending := &pendingTx{msgs: msgs, versions: []*ethtypes.Transaction{tx}, submitted: time.Now()}
+	p.txs[tx.Nonce()] = pending
+	return pending
+}
+
+// trackFill starts tracking a self-transfer filling an account nonce gap. It receives no messages, so it is
+// replaced as a cancel if stuck.
+func (p *pendingTxs) trackFill(tx *ethtypes.Transaction) *pendingTx {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pending := &pendingTx{versions: []*ethtypes.Transaction{tx}, cancel: tx, submitted: time.Now()}
+	p.txs[tx.Nonce()] = pending
+	return pending
+}
+
+// remove stops tracking the tx once it is confirmed or dropped, and reports whether it was still tracked.
+// A tx since submitted with the same account nonce stays tracked.
+func (p *pendingTxs) remove(pending *pendingTx) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	nonce := pending.latest().Nonce()
+	if p.txs[nonce] != pending {
+		return false
+	}
+	delete(p.txs, nonce)
+	return true
+}
+
+// stuck returns the tracked txs of which no version was sent within the timeout
+func (p *pendingTxs) stuck(timeout time.Duration) []*pendingTx {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var stuck []*pendingTx
+	for _, pending := range p.txs {
+		if pending.stuck(timeout) {
+			stuck = append(stuck, pending)
+		}
+	}
+	return stuck
+}
+
+// find returns the tracked tx receiving the message, or nil if there is none
+func (p *pendingTxs) find(msg *types.MessageState) *pendingTx {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, pending := range p.txs {
+		for _, m := range pending.msgs {
+			if m.SourceDomain == msg.SourceDomain && m.Nonce == msg.Nonce {
+				return pending
+			}
+		}
+	}
+	return nil
+}
+
+// replaceStuckTxs replaces the tracked txs that are not mined within the bump timeout until the context is done.
+// Txs are replaced whether or not a broadcast is still waiting on them, so a tx whose messages were requeued after
+// the receipt timeout keeps being bumped.
+func (e *Ethereum) replaceStuckTxs(ctx context.Context, logger log.Logger) {
+	for {
+		timer := time.NewTimer(replaceCheckInterval)
+		select {
+		case <-timer.C:
+			for _, p := range e.pending.stuck(e.bumpTimeout) {
+				receipt, known, err := e.pendingReceipt(ctx, p)
+				switch {
+				case err != nil:
+					logger.Debug("Unable to query receipt", "tx", p.latest().Hash().Hex(), "err", err)
+				case receipt != nil || !known:
+					// mined or dropped txs are left to the broadcast waiting on them
+					p.touch()
+				default:
+					e.replacePending(ctx, logger, p)
+				}
+			}
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// replacePending re-signs a tx that was not mined within the bump timeout with bumped fees, at the same account nonce.
+// If its messages were all received by other txs meanwhile, the tx is cancelled with a self-transfer instead.
+func (e *Ethereum) replacePending(ctx context.Context, logger log.Logger, p *pendingTx) {
+	// the timeout restarts whether or not a replacement could be sent
+	defer p.touch()
+
+	p.mu.Lock()
+	cancelled := p.cancel != nil
+	p.mu.Unlock()
+
+	latest := p.latest()
+	feeCap, tipCap, ok := bumpFees(latest, e.maxFeeCap)
+	if !ok {
+		logger.Error(fmt.Sprintf("Tx %s is not mined, but its fees are already at the max-fee-gwei ceiling", latest.Hash().Hex()), "nonce", latest.Nonce())
+		return
+	}
+
+	to, gas, value, data := latest.To(), latest.Gas(), latest.Value(), latest.Data()
+	cancel := false
+	if !cancelled {
+		used, err := e.allNoncesUsed(ctx, p.msgs)
+		if err != nil {
+			logger.Error("Unable to query used nonce before replacing tx", "err", err)
+			return
+		}
+		if used {
+			cancel = true
+			from := common.HexToAddress(e.minterAddress)
+			to, gas, value, data = &from, params.TxGas, new(big.Int), nil
+		}
+	}
+
+	var txData ethtypes.TxData
+	if latest.Type() == ethtypes.LegacyTxType {
+		txData = &ethtypes.LegacyTx{Nonce: latest.Nonce(), GasPrice: feeCap, Gas: gas, To: to, Value: value, Data: data}
+	} else {
+		txData = &ethtypes.DynamicFeeTx{
+			ChainID:   big.NewInt(e.chainID),
+			Nonce:     latest.Nonce(),
+			GasTipCap: tipCap,
+			GasFeeCap: feeCap,
+			Gas:       gas,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		}
+	}
+
+	replacement, err := ethtypes.SignNewTx(e.privateKey, ethtypes.LatestSignerForChainID(big.NewInt(e.chainID)), txData)
+	if err != nil {
+		logger.Error("Unable to sign replacement tx", "err", err)
+		return
+	}
+	if err := e.rpcClient.SendTransaction(ctx, replacement); err != nil {
+		logger.Error(fmt.Sprintf("Unable to send replacement for tx %s", latest.Hash().Hex()), "err", err)
+		return
+	}
+
+	p.replaced(replacement, cancel)
+	if cancel {
+		logger.Info(fmt.Sprintf("The messages of tx %s were received by other txs. Cancelling it with self-transfer %s",
+			latest.Hash().Hex(), replacement.Hash().Hex()), "account_nonce", latest.Nonce())
+		return
+	}
+	logger.Info(fmt.Sprintf("Tx %s not mined after %v. Replaced with tx %s with max fee %s wei and tip %s wei",
+		latest.Hash().Hex(), e.bumpTimeout, replacement.Hash().Hex(), feeCap, tipCap), "account_nonce", latest.Nonce())
+}
+
+// allNoncesUsed reports whether every message was received
+func (e *Ethereum) allNoncesUsed(ctx context.Context, msgs []*types.MessageState) (bool, error) {
+	for _, msg := range msgs {
+		used, err := e.NonceUsed(ctx, msg.SourceDomain, msg.Nonce)
+		if err != nil || !used {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// bumpFees returns the fee cap and tip of a replacement for the tx, increased by 12.5% and capped at maxFeeCap.
+// It returns false if the capped fees no longer meet the 10% increase nodes require of a replacement.
+func bumpFees(tx *ethtypes.Transaction, maxFeeCap *big.Int) (feeCap, tipCap *big.Int, ok bool) {
+	bump := func(fee *big.Int) *big.Int {
+		bumped := new(big.Int).Mul(fee, big.NewInt(9))
+		bumped.Div(bumped, big.NewInt(8))
+		return bumped.Add(bumped, big.NewInt(1))
+	}
+	minReplacement := func(fee *big.Int) *big.Int {
+		min := new(big.Int).Mul(fee, big.NewInt(11))
+		return min.Div(min.Add(min, big.NewInt(9)), big.NewInt(10))
+	}
+
+	feeCap = bump(tx.GasFeeCap())
+	tipCap = bump(tx.GasTipCap())
+	if maxFeeCap != nil && feeCap.Cmp(maxFeeCap) > 0 {
+		feeCap = new(big.Int).Set(maxFeeCap)
+	}
+	if tipCap.Cmp(feeCap) > 0 {
+		tipCap = new(big.Int).Set(feeCap)
+	}
+
+	if feeCap.Cmp(minReplacement(tx.GasFeeCap())) < 0 || tipCap.Cmp(minReplacement(tx.GasTipCap())) < 0 {
+		return nil, nil, false
+	}
+	return feeCap, tipCap, true
+}
+
+// fillNonceGaps fills the account nonces that were released below the next one and not allocated again within a
+// check interval, until the context is done. A tx dropped while later txs were signed leaves such a gap, and the later
+// txs are not mined until it is filled. Without new messages to broadcast, it is filled with a self-transfer.
+func (e *Ethereum) fillNonceGaps(ctx context.Context, logger log.Logger, sequenceMap *types.SequenceMap) {
+	var fills []*pendingTx
+	var released []uint64
+	for {
+		timer := time.NewTimer(gapCheckInterval)
+		select {
+		case <-timer.C:
+			fills = e.checkFills(ctx, logger, sequenceMap, fills)
+
+			// nonces that were already released at the previous check were not allocated for a whole interval
+			current := sequenceMap.Released(e.domain)
+			for _, nonce := range current {
+				if !slices.Contains(released, nonce) || !sequenceMap.Claim(e.domain, nonce) {
+					continue
+				}
+				if p := e.sendFill(ctx, logger, sequenceMap, nonce); p != nil {
+					fills = append(fills, p)
+				}
+			}
+			released = current
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// sendFill signs and sends a self-transfer with the account nonce, and tracks it so it is replaced if stuck.
+// If it cannot be sent, the account nonce is released again.
+func (e *Ethereum) sendFill(ctx context.Context, logger log.Logger, sequenceMap *types.SequenceMap, nonce uint64) *pendingTx {
+	fees, err := e.fees(ctx)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Unable to fill account nonce gap %d", nonce), "err", err)
+		sequenceMap.Release(e.domain, nonce)
+		return nil
+	}
+
+	from := common.HexToAddress(e.minterAddress)
+	var txData ethtypes.TxData
+	if fees.gasPrice != nil {
+		txData = &ethtypes.LegacyTx{Nonce: nonce, GasPrice: fees.gasPrice, Gas: params.TxGas, To: &from, Value: new(big.Int)}
+	} else {
+		txData = &ethtypes.DynamicFeeTx{
+			ChainID:   big.NewInt(e.chainID),
+			Nonce:     nonce,
+			GasTipCap: fees.tipCap,
+			GasFeeCap: fees.feeCap,
+			Gas:       params.TxGas,
+			To:        &from,
+			Value:     new(big.Int),
+		}
+	}
+
+	tx, err := ethtypes.SignNewTx(e.privateKey, ethtypes.LatestSignerForChainID(big.NewInt(e.chainID)), txData)
+	if err == nil {
+		err = e.rpcClient.SendTransaction(ctx, tx)
+	}
+	if err != nil {
+		logger.Error(fmt.Sprintf("Unable to fill account nonce gap %d", nonce), "err", err)
+		e.handleSendError(ctx, logger, sequenceMap, nonce, err)
+		return nil
+	}
+
+	logger.Info(fmt.Sprintf("Account nonce %d was left unused by a dropped tx. Filled it with self-transfer %s", nonce, tx.Hash().Hex()))
+	return e.pending.trackFill(tx)
+}
+
+// checkFills stops tracking the fill txs that were mined or dropped, and returns the rest. The account nonce of a
+// dropped fill is released again.
+func (e *Ethereum) checkFills(ctx context.Context, logger log.Logger, sequenceMap *types.SequenceMap, fills []*pendingTx) []*pendingTx {
+	var unconfirmed []*pendingTx
+	for _, p := range fills {
+		receipt, known, err := e.pendingReceipt(ctx, p)
+		switch {
+		case err != nil:
+			logger.Debug("Unable to query receipt", "tx", p.latest().Hash().Hex(), "err", err)
+			unconfirmed = append(unconfirmed, p)
+		case receipt != nil:
+			e.pending.remove(p)
+		case !known:
+			if e.pending.remove(p) {
+				sequenceMap.Release(e.domain, p.latest().Nonce())
+			}
+		default:
+			unconfirmed = append(unconfirmed, p)
+		}
+	}
+	return unconfirmed
+}
@@ -0,0 +1,69 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+func TestBumpFees(t *testing.T) {
+	tx := ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		GasFeeCap: big.NewInt(100),
+		GasTipCap: big.NewInt(10),
+	})
+
+	// bumped by 12.5%
+	feeCap, tipCap, ok := bumpFees(tx, big.NewInt(1000))
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(113), feeCap)
+	require.Equal(t, big.NewInt(12), tipCap)
+
+	// capped at the ceiling, still a 10% increase
+	feeCap, _, ok = bumpFees(tx, big.NewInt(110))
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(110), feeCap)
+
+	// the ceiling is too low for a replacement
+	_, _, ok = bumpFees(tx, big.NewInt(105))
+	require.False(t, ok)
+
+	// legacy txs bump their gas price
+	legacy := ethtypes.NewTx(&ethtypes.LegacyTx{GasPrice: big.NewInt(80)})
+	feeCap, tipCap, ok = bumpFees(legacy, big.NewInt(1000))
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(91), feeCap)
+	require.Equal(t, feeCap, tipCap)
+}
+
+func TestPendingTxs(t *testing.T) {
+	pending := newPendingTxs()
+	msg := &types.MessageState{SourceDomain: 0, Nonce: 7}
+	p := pending.track([]*types.MessageState{msg}, ethtypes.NewTx(&ethtypes.LegacyTx{Nonce: 3}))
+
+	// a requeued message finds its pending tx by source domain and nonce
+	require.Equal(t, p, pending.find(&types.MessageState{SourceDomain: 0, Nonce: 7}))
+	require.Nil(t, pending.find(&types.MessageState{SourceDomain: 1, Nonce: 7}))
+
+	require.Empty(t, pending.stuck(time.Minute))
+	require.Equal(t, []*pendingTx{p}, pending.stuck(0))
+
+	// a tx sent since with the same account nonce stays tracked
+	next := pending.track([]*types.MessageState{msg}, ethtypes.NewTx(&ethtypes.LegacyTx{Nonce: 3}))
+	pending.remove(p)
+	require.Equal(t, next, pending.find(msg))
+	pending.remove(next)
+	require.Nil(t, pending.find(msg))
+
+	// a gap fill receives no messages, and is replaced as a cancel
+	fillTx := ethtypes.NewTx(&ethtypes.LegacyTx{Nonce: 4})
+	fill := pending.trackFill(fillTx)
+	require.True(t, fill.isCancel(fillTx.Hash()))
+	require.False(t, fill.multicall())
+	require.Nil(t, pending.find(msg))
+	require.True(t, pending.remove(fill))
+}
@@ -0,0 +1,170 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+const (
+	defaultConfirmations  = 1
+	defaultReceiptTimeout = 5 * time.Minute
+
+	receiptPollInterval = 3 * time.Second
+	// droppedTxTimeout is how long a submitted tx may be unknown to the rpc node before it is considered dropped
+	droppedTxTimeout = time.Minute
+)
+
+var (
+	// errTxDropped is returned when a submitted tx is no longer known to the rpc node
+	errTxDropped = errors.New("tx was dropped")
+	// errTxPending is returned when a submitted tx is not confirmed within the receipt timeout. It may still be mined.
+	errTxPending = types.ErrTxPending
+)
+
+// awaitReceipt waits until the pending tx of the message is confirmed and records its result on the message.
+// The message is only marked complete on a successful receipt. A *RevertError is returned if the tx reverted,
+// errTxDropped if it was dropped, in which case its account nonce is released. If the tx is not confirmed within
+// the receipt timeout, errTxPending is returned and the tx stays tracked, so that it is waited on again instead of
+// a new tx being sent.
+func (e *Ethereum) awaitReceipt(
+	ctx context.Context,
+	logger log.Logger,
+	msg *types.MessageState,
+	from common.Address,
+	p *pendingTx,
+	sequenceMap *types.SequenceMap,
+) error {
+	receipt, err := e.waitForReceipt(ctx, logger, p)
+	if errors.Is(err, errTxDropped) {
+		msg.DestTxHash = ""
+		// the tx may be awaited for several msgs, its account nonce is released once
+		if e.pending.remove(p) {
+			sequenceMap.Release(e.domain, p.latest().Nonce())
+		}
+	}
+	if err != nil {
+		return err
+	}
+	e.pending.remove(p)
+
+	if p.multicall() {
+		return e.recordMulticallReceipt(msg, p, receipt)
+	}
+
+	msg.GasUsed = receipt.GasUsed
+	msg.EffectiveGasPrice = receipt.EffectiveGasPrice
+
+	if p.isCancel(receipt.TxHash) {
+		// the message was received by another tx, the cancel only freed the account nonce
+		msg.Status = types.Complete
+		msg.DestTxHash = ""
+		logger.Info(fmt.Sprintf("Cancel tx %s confirmed in block %d, %s was received by another tx", receipt.TxHash.Hex(), receipt.BlockNumber, msg.SourceTxHash))
+		return nil
+	}
+
+	// a replacement may have been mined instead of the original tx
+	tx := p.version(receipt.TxHash)
+	msg.DestTxHash = tx.Hash().Hex()
+
+	if receipt.Status == ethtypes.ReceiptStatusSuccessful {
+		msg.Status = types.Complete
+		logger.Info(fmt.Sprintf("Tx %s for %s confirmed in block %d. Gas used: %d", tx.Hash().Hex(), msg.SourceTxHash, receipt.BlockNumber, receipt.GasUsed))
+		return nil
+	}
+
+	return &RevertError{TxHash: tx.Hash().Hex(), Reason: e.revertReason(ctx, from, tx, receipt)}
+}
+
+// waitForReceipt polls the receipts of every version of the pending tx until one has the configured number of confirmations.
+// The receipts are queried again on every poll, so a tx that is reorged out is waited on again.
+func (e *Ethereum) waitForReceipt(ctx context.Context, logger log.Logger, p *pendingTx) (*ethtypes.Receipt, error) {
+	deadline := time.Now().Add(e.receiptTimeout)
+	var unknownSince time.Time
+
+	for {
+		receipt, known, err := e.pendingReceipt(ctx, p)
+		switch {
+		case err != nil:
+			logger.Debug("Unable to query receipt", "tx", p.latest().Hash().Hex(), "err", err)
+		case receipt != nil:
+			unknownSince = time.Time{}
+			latest, err := e.QueryLatestBlock(ctx)
+			if err != nil {
+				logger.Debug("Unable to query latest block", "err", err)
+				break
+			}
+			if latest+1 >= receipt.BlockNumber.Uint64()+e.confirmations {
+				return receipt, nil
+			}
+		case !known:
+			if unknownSince.IsZero() {
+				unknownSince = time.Now()
+			} else if time.Since(unknownSince) > droppedTxTimeout {
+				return nil, fmt.Errorf("%w: %s", errTxDropped, p.latest().Hash().Hex())
+			}
+		default:
+			// a pending tx has no receipt yet, but is still known to the node. It is replaced by replaceStuckTxs if stuck
+			unknownSince = time.Time{}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %s not confirmed after %v", errTxPending, p.latest().Hash().Hex(), e.receiptTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(receiptPollInterval):
+		}
+	}
+}
+
+// pendingReceipt returns the receipt of whichever version of the pending tx was mined, or nil if none was.
+// known is false when no version of the tx is known to the node anymore.
+func (e *Ethereum) pendingReceipt(ctx context.Context, p *pendingTx) (receipt *ethtypes.Receipt, known bool, err error) {
+	versions := p.signed()
+	for _, tx := range versions {
+		receipt, err := e.rpcClient.TransactionReceipt(ctx, tx.Hash())
+		if err == nil {
+			return receipt, true, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, true, err
+		}
+	}
+	for _, tx := range versions {
+		if _, _, err := e.rpcClient.TransactionByHash(ctx, tx.Hash()); !errors.Is(err, ethereum.NotFound) {
+			return nil, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// revertReason replays a reverted tx on the state before its block and returns the revert reason.
+func (e *Ethereum) revertReason(ctx context.Context, from common.Address, tx *ethtypes.Transaction, receipt *ethtypes.Receipt) string {
+	_, err := e.rpcClient.CallContract(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, new(big.Int).Sub(receipt.BlockNumber, big.NewInt(1)))
+	if err != nil {
+		return decodeRevert(err)
+	}
+	if receipt.GasUsed == tx.Gas() {
+		return "out of gas"
+	}
+	return "unknown"
+}
@@ -0,0 +1,150 @@
+package ethereum
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum/contracts"
+)
+
+var (
+	// ErrNonceUsed is returned when the message was already received. Its messages are complete.
+	ErrNonceUsed = errors.New("nonce already used")
+	// ErrInvalidAttestation is returned when the attestation was not signed by the enabled attesters. Its messages fail.
+	ErrInvalidAttestation = errors.New("invalid attestation")
+	// ErrInvalidMessage is returned when the message can not be received on the chain. Its messages fail.
+	ErrInvalidMessage = errors.New("invalid message")
+	// ErrReceiverFailed is returned when the message recipient, ex. the TokenMessenger, failed to handle the message. Its messages fail.
+	ErrReceiverFailed = errors.New("message handler failed")
+	// ErrTransmitterPaused is returned while the MessageTransmitter is paused. Its messages are retried.
+	ErrTransmitterPaused = errors.New("message transmitter paused")
+)
+
+// revertErrors maps MessageTransmitter revert reasons to typed errors
+var revertErrors = map[string]error{
+	"Nonce already used":              ErrNonceUsed,
+	"Invalid attestation length":      ErrInvalidAttestation,
+	"Invalid signature order or dupe": ErrInvalidAttestation,
+	"Invalid signature: not attester": ErrInvalidAttestation,
+	"Invalid destination domain":      ErrInvalidMessage,
+	"Invalid caller for message":      ErrInvalidMessage,
+	"Invalid message version":         ErrInvalidMessage,
+	"Invalid message length":          ErrInvalidMessage,
+	"handleReceiveMessage() failed":   ErrReceiverFailed,
+	"Pausable: paused":                ErrTransmitterPaused,
+}
+
+// revertAction is what is done with a message whose ReceiveMessage call reverted
+type revertAction int
+
+const (
+	actionRetry revertAction = iota
+	actionComplete
+	actionFail
+)
+
+// RevertError is returned when a ReceiveMessage call reverted, either in a simulation or in a mined tx.
+// It unwraps to the typed error of its revert reason, if the reason is known.
+type RevertError struct {
+	// TxHash is empty when the call was simulated
+	TxHash string
+	Reason string
+}
+
+func (e *RevertError) Error() string {
+	if e.TxHash == "" {
+		return fmt.Sprintf("simulated ReceiveMessage reverted: %s", e.Reason)
+	}
+	return fmt.Sprintf("tx %s reverted: %s", e.TxHash, e.Reason)
+}
+
+func (e *RevertError) Unwrap() error {
+	return revertErrors[e.Reason]
+}
+
+// action returns what is done with the messages of the reverted call. Unknown reverts are retried.
+func (e *RevertError) action() revertAction {
+	switch {
+	case errors.Is(e, ErrNonceUsed):
+		return actionComplete
+	case errors.Is(e, ErrInvalidAttestation), errors.Is(e, ErrInvalidMessage), errors.Is(e, ErrReceiverFailed):
+		return actionFail
+	default:
+		return actionRetry
+	}
+}
+
+// simulateReceive estimates the gas of a ReceiveMessage call and simulates it with eth_call against the pending state.
+// A revert is returned as a *RevertError.
+func (e *Ethereum) simulateReceive(ctx context.Context, from common.Address, msgSentBytes, attestation []byte) (uint64, error) {
+	parsed, err := contracts.MessageTransmitterMetaData.GetAbi()
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse MessageTransmitter abi: %w", err)
+	}
+	data, err := parsed.Pack("receiveMessage", msgSentBytes, attestation)
+	if err != nil {
+		return 0, fmt.Errorf("unable to pack receiveMessage: %w", err)
+	}
+
+	to := common.HexToAddress(e.messageTransmitterAddress)
+	call := ethereum.CallMsg{From: from, To: &to, Data: data}
+	gas, err := e.rpcClient.EstimateGas(ctx, call)
+	if err != nil {
+		return 0, callError(err)
+	}
+
+	call.Gas = gas
+	if _, err := e.rpcClient.PendingCallContract(ctx, call); err != nil {
+		return 0, callError(err)
+	}
+	return gas, nil
+}
+
+// callError returns a *RevertError if the call reverted, otherwise the error
+func callError(err error) error {
+	var dataErr rpc.DataError
+	if (errors.As(err, &dataErr) && dataErr.ErrorData() != nil) || strings.Contains(err.Error(), "execution reverted") {
+		return &RevertError{Reason: decodeRevert(err)}
+	}
+	return err
+}
+
+// decodeRevert returns the revert reason of a failed call, from the error data if the node returned any.
+// Custom errors in the MessageTransmitter abi are returned by name.
+func decodeRevert(err error) string {
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			revertData := common.FromHex(data)
+			if reason, err := abi.UnpackRevert(revertData); err == nil {
+				return reason
+			}
+			if name, ok := customError(revertData); ok {
+				return name
+			}
+		}
+	}
+	return strings.TrimPrefix(err.Error(), "execution reverted: ")
+}
+
+// customError returns the name of the MessageTransmitter abi error the revert data encodes
+func customError(revertData []byte) (string, bool) {
+	parsed, err := contracts.MessageTransmitterMetaData.GetAbi()
+	if err != nil || len(revertData) < 4 {
+		return "", false
+	}
+	for name, abiErr := range parsed.Errors {
+		if bytes.Equal(abiErr.ID[:4], revertData[:4]) {
+			return name, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,32 @@
+package ethereum_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum"
+)
+
+func TestRevertErrorTypes(t *testing.T) {
+	testCases := []struct {
+		reason   string
+		expected error
+	}{
+		{"Nonce already used", ethereum.ErrNonceUsed},
+		{"Invalid attestation length", ethereum.ErrInvalidAttestation},
+		{"Invalid signature: not attester", ethereum.ErrInvalidAttestation},
+		{"Invalid destination domain", ethereum.ErrInvalidMessage},
+		{"handleReceiveMessage() failed", ethereum.ErrReceiverFailed},
+		{"Pausable: paused", ethereum.ErrTransmitterPaused},
+	}
+
+	for _, tc := range testCases {
+		var err error = &ethereum.RevertError{Reason: tc.reason}
+		require.ErrorIs(t, err, tc.expected, tc.reason)
+	}
+
+	var err error = &ethereum.RevertError{TxHash: "0xabc", Reason: "out of gas"}
+	require.NotErrorIs(t, err, ethereum.ErrNonceUsed)
+	require.Equal(t, "tx 0xabc reverted: out of gas", err.Error())
+}
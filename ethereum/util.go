@@ -2,12 +2,11 @@ package ethereum
 
 import (
 	"crypto/ecdsa"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"math/big"
 
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/rpc"
 )
 
 type JSONError interface {
@@ -16,23 +15,6 @@ type JSONError interface {
 	ErrorData() interface{}
 }
 
-func GetEthereumAccountNonce(endpoint string, address string) (int64, error) {
-	client, err := rpc.Dial(endpoint)
-	if err != nil {
-		return 0, fmt.Errorf("failed to connect to Ethereum RPC at %s: %v", endpoint, err)
-	}
-
-	var result string
-	if err := client.Call(&result, "eth_getTransactionCount", address, "pending"); err != nil {
-		return 0, fmt.Errorf("failed to get transaction count: %v", err)
-	}
-
-	nonce := new(big.Int)
-	nonce.SetString(result[2:], 16) // Removing "0x" prefix and converting hex string to *big.Int
-
-	return nonce.Int64(), nil
-}
-
 // GetEcdsaKeyAddress returns the public ecdsa key and address given the private key
 func GetEcdsaKeyAddress(privateKey string) (*ecdsa.PrivateKey, string, error) {
 	privEcdsaKey, err := crypto.HexToECDSA(privateKey)
@@ -48,3 +30,12 @@ func GetEcdsaKeyAddress(privateKey string) (*ecdsa.PrivateKey, string, error) {
 
 	return privEcdsaKey, crypto.PubkeyToAddress(*publicKeyECDSA).Hex(), nil
 }
+
+// GenerateKey returns a new hex encoded private key and its address
+func GenerateKey() (privateKey string, address string, err error) {
+	privEcdsaKey, err := crypto.GenerateKey()
+	if err != nil {
+		return "", "", fmt.Errorf("unable to generate key: %w", err)
+	}
+	return hex.EncodeToString(crypto.FromECDSA(privEcdsaKey)), crypto.PubkeyToAddress(privEcdsaKey.PublicKey).Hex(), nil
+}
@@ -9,14 +9,6 @@ import (
 	testutil "github.com/strangelove-ventures/noble-cctp-relayer/test_util"
 )
 
-func TestGetEthereumAccountNonce(t *testing.T) {
-	a, _ := testutil.ConfigSetup(t)
-	ethConfig := a.Config.Chains["ethereum"].(*ethereum.ChainConfig)
-
-	_, err := ethereum.GetEthereumAccountNonce(ethConfig.RPC, "0x4996f29b254c77972fff8f25e6f7797b3c9a0eb6")
-	require.NoError(t, err)
-}
-
 // Return public ecdsa key and address given the private key
 func TestGetEcdsaKeyAddress(t *testing.T) {
 	a, _ := testutil.ConfigSetup(t)
@@ -27,3 +19,13 @@ func TestGetEcdsaKeyAddress(t *testing.T) {
 	require.NotNil(t, addr)
 	require.NoError(t, err)
 }
+
+func TestGenerateKey(t *testing.T) {
+	privKey, addr, err := ethereum.GenerateKey()
+	require.NoError(t, err)
+
+	// the generated key is in the format expected by the config
+	_, derived, err := ethereum.GetEcdsaKeyAddress(privKey)
+	require.NoError(t, err)
+	require.Equal(t, addr, derived)
+}
@@ -24,6 +24,7 @@ require (
 	github.com/joho/godotenv v1.5.1
 	github.com/pascaldekloe/etherstream v0.1.0
 	github.com/prometheus/client_golang v1.14.0
+	go.etcd.io/bbolt v1.3.7
 	google.golang.org/grpc v1.60.0
 	gopkg.in/yaml.v2 v2.4.0
 )
@@ -162,7 +163,6 @@ require (
 	github.com/ugorji/go/codec v1.2.11 // indirect
 	github.com/zondax/hid v0.9.1 // indirect
 	github.com/zondax/ledger-go v0.14.1 // indirect
-	go.etcd.io/bbolt v1.3.7 // indirect
 	go.uber.org/multierr v1.10.0 // indirect
 	golang.org/x/arch v0.3.0 // indirect
 	golang.org/x/crypto v0.21.0 // indirect
@@ -28,7 +28,6 @@ import (
 	"cosmossdk.io/math"
 
 	"github.com/strangelove-ventures/noble-cctp-relayer/cosmos"
-	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum"
 	"github.com/strangelove-ventures/noble-cctp-relayer/ethereum/contracts"
 )
 
@@ -248,9 +247,9 @@ func TestEthBurnToNobleDeployed(t *testing.T) {
 	require.NoError(t, err)
 
 	// deal w/ nonce
-	nextNonce, err := ethereum.GetEthereumAccountNonce(ethConfig.RPC, ethConfig.Address)
+	nextNonce, err := client.PendingNonceAt(ctx, common.HexToAddress(ethConfig.Address))
 	require.NoError(t, err)
-	auth.Nonce = big.NewInt(nextNonce)
+	auth.Nonce = new(big.Int).SetUint64(nextNonce)
 
 	// Approve erc20 to interact with contract up to the sum of the amount being burnt
 	erc20, err := NewERC20(common.HexToAddress(ethConfig.UsdcTokenAddress), client)
@@ -275,7 +274,7 @@ func TestEthBurnToNobleDeployed(t *testing.T) {
 	require.NoError(t, err)
 	destinationCallerPadded := append([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, destinationCallerRaw...)
 
-	auth.Nonce = big.NewInt(nextNonce + 1)
+	auth.Nonce = new(big.Int).SetUint64(nextNonce + 1)
 
 	tx, err := tokenMessenger.DepositForBurnWithCaller(
 		auth,
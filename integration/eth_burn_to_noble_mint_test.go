@@ -73,7 +73,7 @@ func TestEthBurnToNobleMint(t *testing.T) {
 
 	processingQueue := make(chan *types.TxState, 10)
 
-	go ethChain.StartListener(ctx, a.Logger, processingQueue, false, 0)
+	go ethChain.StartListener(ctx, a.Logger, processingQueue, types.NewCheckpoints(), false, 0)
 	go cmd.StartProcessor(ctx, a, registeredDomains, processingQueue, sequenceMap, nil)
 
 	_, _, generatedWallet := testdata.KeyTestPubAddr()
@@ -102,9 +102,9 @@ func TestEthBurnToNobleMint(t *testing.T) {
 	// deal w/ nonce
 	ethRelayerAddress, err := ethConvertPrivateKeytoAddress(ethCfg.MinterPrivateKey)
 	require.NoError(t, err)
-	nextNonce, err := ethereum.GetEthereumAccountNonce(ethCfg.RPC, ethRelayerAddress)
+	nextNonce, err := client.PendingNonceAt(ctx, common.HexToAddress(ethRelayerAddress))
 	require.NoError(t, err)
-	auth.Nonce = big.NewInt(nextNonce)
+	auth.Nonce = new(big.Int).SetUint64(nextNonce)
 
 	// Approve erc20 to interact with contract up to the sum of the amount being burnt
 	erc20, err := NewERC20(common.HexToAddress(usdcTokenAddressSepolia), client)
@@ -123,7 +123,7 @@ func TestEthBurnToNobleMint(t *testing.T) {
 	mintRecipientPadded := append([]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}, generatedWallet...)
 	require.NoError(t, err)
 
-	auth.Nonce = big.NewInt(nextNonce + 1)
+	auth.Nonce = new(big.Int).SetUint64(nextNonce + 1)
 
 	// destination caller
 	callerPrivKey := nobleCfg.MinterPrivateKey
@@ -5,11 +5,15 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"time"
 
 	nobletypes "github.com/circlefin/noble-cctp/x/cctp/types"
+	"google.golang.org/grpc/status"
+
+	coretypes "github.com/cometbft/cometbft/rpc/core/types"
 
 	sdkclient "github.com/cosmos/cosmos-sdk/client"
 	clientTx "github.com/cosmos/cosmos-sdk/client/tx"
@@ -26,8 +30,22 @@ import (
 	"github.com/strangelove-ventures/noble-cctp-relayer/types"
 )
 
+const (
+	defaultGasAdjustment    = 1.5
+	defaultInclusionTimeout = time.Minute
+
+	inclusionPollInterval = 2 * time.Second
+
+	defaultBatchMaxMsgs = 50
+)
+
 var (
 	regexAccountSequenceMismatchErr = regexp.MustCompile(`expected (\d+), got (\d+)`)
+
+	// errNotIncluded is returned when a broadcast tx failed in DeliverTx or was not included before the inclusion timeout
+	errNotIncluded = errors.New("tx not included")
+	// errTxRejected is returned when one of the msgs of a tx failed, in simulation or in DeliverTx
+	errTxRejected = errors.New("tx rejected")
 )
 
 func (n *Noble) InitializeBroadcaster(
@@ -46,6 +64,8 @@ func (n *Noble) InitializeBroadcaster(
 	return nil
 }
 
+// Broadcast mints the msgs in a single tx. With batching enabled, the msgs are minted together with the msgs of
+// concurrent broadcasts, and Broadcast returns once they were minted.
 func (n *Noble) Broadcast(
 	ctx context.Context,
 	logger log.Logger,
@@ -53,20 +73,89 @@ func (n *Noble) Broadcast(
 	sequenceMap *types.SequenceMap,
 	m *relayer.PromMetrics,
 ) error {
-	// set up sdk context
-	interfaceRegistry := codectypes.NewInterfaceRegistry()
-	nobletypes.RegisterInterfaces(interfaceRegistry)
-	cdc := codec.NewProtoCodec(interfaceRegistry)
-	sdkContext := sdkclient.Context{
-		TxConfig: xauthtx.NewTxConfig(cdc, xauthtx.DefaultSignModes),
+	if n.batchWindow == 0 {
+		return n.broadcastWithRetries(ctx, logger, msgs, sequenceMap, m)
+	}
+
+	n.batcherOnce.Do(func() {
+		// a batch holds the msgs of many broadcasts, so it is not cancelled with the broadcast that started it
+		n.batcher = relayer.NewBatcher(context.WithoutCancel(ctx), n.batchWindow, n.batchMaxMsgs, func(ctx context.Context, batch []*types.MessageState) error {
+			return n.broadcastBatch(ctx, logger, batch, sequenceMap, m)
+		})
+	})
+
+	// the batch error may be caused by the msgs of other broadcasts
+	err := n.batcher.Submit(ctx, msgs...)
+	for _, msg := range msgs {
+		if msg.Status != types.Complete {
+			if err == nil {
+				err = fmt.Errorf("message with nonce %d was not minted", msg.Nonce)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// broadcastBatch mints a batch of msgs from many txs in a single tx, retrying up to the configured broadcast retries.
+// If the tx is rejected for one of its msgs, the batch is split in halves which are minted separately, so that a
+// failing msg does not hold back the others. Other errors leave the msgs attested, to be requeued.
+func (n *Noble) broadcastBatch(
+	ctx context.Context,
+	logger log.Logger,
+	msgs []*types.MessageState,
+	sequenceMap *types.SequenceMap,
+	m *relayer.PromMetrics,
+) error {
+	if len(msgs) == 1 {
+		return n.broadcastWithRetries(ctx, logger, msgs, sequenceMap, m)
 	}
 
+	sdkContext := newSDKContext()
+	txBuilder := sdkContext.TxConfig.NewTxBuilder()
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = n.attemptBroadcast(ctx, logger, msgs, sequenceMap, sdkContext, txBuilder)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, errTxRejected) || attempt >= n.maxRetries {
+			break
+		}
+		logger.Error(fmt.Sprintf("Broadcasting a batch of %d messages to noble failed. Attempt %d/%d Retrying...", len(msgs), attempt, n.maxRetries), "error", err, "interval_seconds", n.retryIntervalSeconds)
+		time.Sleep(time.Duration(n.retryIntervalSeconds) * time.Second)
+	}
+
+	if !errors.Is(err, errTxRejected) {
+		return err
+	}
+
+	logger.Info(fmt.Sprintf("Minting a batch of %d messages failed. Splitting it...", len(msgs)), "error", err)
+	half := len(msgs) / 2
+	return errors.Join(
+		n.broadcastBatch(ctx, logger, msgs[:half], sequenceMap, m),
+		n.broadcastBatch(ctx, logger, msgs[half:], sequenceMap, m),
+	)
+}
+
+// broadcastWithRetries mints the msgs in a single tx, retrying up to the configured broadcast retries.
+func (n *Noble) broadcastWithRetries(
+	ctx context.Context,
+	logger log.Logger,
+	msgs []*types.MessageState,
+	sequenceMap *types.SequenceMap,
+	m *relayer.PromMetrics,
+) error {
+	sdkContext := newSDKContext()
+
 	// build txn
 	txBuilder := sdkContext.TxConfig.NewTxBuilder()
 
 	// sign and broadcast txn
+	var err error
 	for attempt := 1; attempt <= n.maxRetries; attempt++ {
-		err := n.attemptBroadcast(ctx, logger, msgs, sequenceMap, sdkContext, txBuilder)
+		err = n.attemptBroadcast(ctx, logger, msgs, sequenceMap, sdkContext, txBuilder)
 		if err == nil {
 			return nil
 		}
@@ -76,6 +165,11 @@ func (n *Noble) Broadcast(
 		time.Sleep(time.Duration(n.retryIntervalSeconds) * time.Second)
 	}
 
+	// messages whose last tx was not included stay attested, so that the processor requeues them
+	if errors.Is(err, errNotIncluded) {
+		return err
+	}
+
 	for _, msg := range msgs {
 		if msg.Status != types.Complete {
 			msg.Status = types.Failed
@@ -95,11 +189,233 @@ func (n *Noble) attemptBroadcast(
 	sdkContext sdkclient.Context,
 	txBuilder sdkclient.TxBuilder,
 ) error {
+	// an earlier tx of the msgs may still be included, so no other tx is signed for them until it is evicted
+	if err := n.checkSubmitted(ctx, logger, msgs, sequenceMap); err != nil {
+		return err
+	}
+
+	receiveMsgs, err := n.receiveMsgs(ctx, logger, msgs)
+	if err != nil {
+		return err
+	}
+
+	if len(receiveMsgs) == 0 {
+		return nil
+	}
+
+	for _, msg := range msgs {
+		if msg.Status == types.Complete {
+			continue
+		}
+		logger.Info(fmt.Sprintf(
+			"Broadcasting message from %d to %d: with source tx hash %s",
+			msg.SourceDomain,
+			msg.DestDomain,
+			msg.SourceTxHash))
+	}
+
+	gas, accountSequence, rpcResponse, err := n.signAndBroadcast(ctx, logger, receiveMsgs, sequenceMap, sdkContext, txBuilder)
+	if err != nil {
+		return err
+	}
+	submitted := &submittedTx{hash: rpcResponse.Hash, sequence: accountSequence}
+	n.submitted.track(msgs, submitted)
+
+	// Tx passed CheckTx, it is complete once it is included
+	for _, msg := range msgs {
+		if msg.Status != types.Complete {
+			msg.DestTxHash = rpcResponse.Hash.String()
+		}
+	}
+
+	logger.Info(fmt.Sprintf("Successfully broadcast %s to Noble.  Tx hash: %s. Waiting for inclusion", msgs[0].SourceTxHash, rpcResponse.Hash))
+
+	err = n.waitForInclusion(ctx, rpcResponse.Hash)
+	if err == nil || errors.Is(err, errTxRejected) {
+		// the tx was included, so its account sequence was used
+		n.submitted.remove(submitted)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range msgs {
+		msg.Status = types.Complete
+	}
+
+	logger.Info(fmt.Sprintf("Tx %s for %s included. Gas limit: %d", rpcResponse.Hash, msgs[0].SourceTxHash, gas))
+
+	return nil
+}
+
+// signAndBroadcast signs the tx with the next account sequence and broadcasts it, returning its gas limit and account
+// sequence. Txs are signed and broadcast one at a time, so that they reach the mempool in account sequence order.
+// The account sequence is released if no tx was accepted with it.
+func (n *Noble) signAndBroadcast(
+	ctx context.Context,
+	logger log.Logger,
+	receiveMsgs []sdk.Msg,
+	sequenceMap *types.SequenceMap,
+	sdkContext sdkclient.Context,
+	txBuilder sdkclient.TxBuilder,
+) (uint64, uint64, *coretypes.ResultBroadcastTx, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	accountSequence := sequenceMap.Next(n.Domain())
+
+	gas, err := n.estimateGas(ctx, sdkContext, txBuilder, receiveMsgs, accountSequence)
+	if err != nil {
+		sequenceMap.Release(n.Domain(), accountSequence)
+		return 0, 0, nil, err
+	}
+
+	txBytes, err := n.signTx(sdkContext, txBuilder, receiveMsgs, accountSequence, gas)
+	if err != nil {
+		sequenceMap.Release(n.Domain(), accountSequence)
+		return 0, 0, nil, err
+	}
+
+	rpcResponse, err := n.cc.RPCClient.BroadcastTxSync(ctx, txBytes)
+	if err != nil {
+		sequenceMap.Release(n.Domain(), accountSequence)
+		return 0, 0, nil, err
+	}
+
+	switch rpcResponse.Code {
+	case 0:
+		return gas, accountSequence, rpcResponse, nil
+	case 32:
+		newAccountSequence := n.extractAccountSequence(ctx, logger, rpcResponse.Log)
+		logger.Debug(fmt.Sprintf("retrying with new account sequence: %d", newAccountSequence))
+		sequenceMap.Put(n.Domain(), newAccountSequence)
+	default:
+		// the tx failed CheckTx, so its account sequence was not used
+		sequenceMap.Release(n.Domain(), accountSequence)
+	}
+	return 0, 0, nil, fmt.Errorf("received non-zero: %d - %s", rpcResponse.Code, rpcResponse.Log)
+}
+
+// estimateGas simulates the tx and returns its gas used scaled by the gas adjustment, capped at the gas limit per msg.
+func (n *Noble) estimateGas(
+	ctx context.Context,
+	sdkContext sdkclient.Context,
+	txBuilder sdkclient.TxBuilder,
+	receiveMsgs []sdk.Msg,
+	accountSequence uint64,
+) (uint64, error) {
+	// the gas limit of a simulated tx is not enforced
+	txBytes, err := n.signTx(sdkContext, txBuilder, receiveMsgs, accountSequence, n.gasLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := n.cc.SimulateTx(ctx, txBytes)
+	if err != nil {
+		// the node answers a failed simulation with a query error, transport errors are not
+		if _, ok := status.FromError(err); ok {
+			return 0, fmt.Errorf("%w: simulation failed: %w", errTxRejected, err)
+		}
+		return 0, fmt.Errorf("unable to simulate tx: %w", err)
+	}
+
+	// the gas limit applies per msg, so that batched txs are not starved
+	maxGas := n.gasLimit * uint64(len(receiveMsgs))
+	gasUsed := res.GasInfo.GasUsed
+	if maxGas > 0 && gasUsed > maxGas {
+		return 0, fmt.Errorf("%w: simulated gas %d is above the gas limit %d", errTxRejected, gasUsed, maxGas)
+	}
+	gas := uint64(math.Ceil(float64(gasUsed) * n.gasAdjustment))
+	if maxGas > 0 && gas > maxGas {
+		gas = maxGas
+	}
+	return gas, nil
+}
+
+// waitForInclusion polls the tx until it is included in a block and checks its DeliverTx result.
+// An error wrapping errNotIncluded is returned if it failed, or was not included before the inclusion timeout.
+func (n *Noble) waitForInclusion(ctx context.Context, hash []byte) error {
+	deadline := time.Now().Add(n.inclusionTimeout)
+	for {
+		// the tx is not found until it is included
+		res, err := n.cc.RPCClient.Tx(ctx, hash, false)
+		if err == nil {
+			if res.TxResult.Code != 0 {
+				return fmt.Errorf("%w: %w: tx %X failed in block %d with code %d: %s", errNotIncluded, errTxRejected, hash, res.Height, res.TxResult.Code, res.TxResult.Log)
+			}
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%w: tx %X not included after %v", errNotIncluded, hash, n.inclusionTimeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(inclusionPollInterval):
+		}
+	}
+}
+
+// Simulate signs a tx receiving the msgs with the current account sequence and estimates its gas like Broadcast,
+// without broadcasting it.
+func (n *Noble) Simulate(
+	ctx context.Context,
+	logger log.Logger,
+	msgs []*types.MessageState,
+) error {
+	sdkContext := newSDKContext()
+	txBuilder := sdkContext.TxConfig.NewTxBuilder()
+
+	receiveMsgs, err := n.receiveMsgs(ctx, logger, msgs)
+	if err != nil {
+		return err
+	}
+
+	if len(receiveMsgs) == 0 {
+		return nil
+	}
+
+	// the account sequence is queried so that the relayer's sequence map is left untouched
+	_, accountSequence, err := n.AccountInfo(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get account info for noble: %w", err)
+	}
+
+	gas, err := n.estimateGas(ctx, sdkContext, txBuilder, receiveMsgs, accountSequence)
+	if err != nil {
+		for _, msg := range msgs {
+			if msg.Status != types.Complete {
+				msg.Status = types.Failed
+			}
+		}
+		return fmt.Errorf("simulation failed: %w", err)
+	}
+
+	for _, msg := range msgs {
+		if msg.Status != types.Complete {
+			msg.Status = types.Simulated
+		}
+	}
+
+	logger.Info(fmt.Sprintf("Dry run: simulated %s on Noble. Gas limit: %d", msgs[0].SourceTxHash, gas))
+
+	return nil
+}
+
+// receiveMsgs builds a MsgReceiveMessage for each message that was not received yet.
+// Messages that were already received are marked complete.
+func (n *Noble) receiveMsgs(
+	ctx context.Context,
+	logger log.Logger,
+	msgs []*types.MessageState,
+) ([]sdk.Msg, error) {
 	var receiveMsgs []sdk.Msg
 	for _, msg := range msgs {
 		used, err := n.cc.QueryUsedNonce(ctx, msg.SourceDomain, msg.Nonce)
 		if err != nil {
-			return fmt.Errorf("unable to query used nonce: %w", err)
+			return nil, fmt.Errorf("unable to query used nonce: %w", err)
 		}
 
 		if used {
@@ -115,7 +431,7 @@ func (n *Noble) attemptBroadcast(
 
 		attestationBytes, err := hex.DecodeString(msg.Attestation[2:])
 		if err != nil {
-			return fmt.Errorf("unable to decode message attestation")
+			return nil, fmt.Errorf("unable to decode message attestation")
 		}
 
 		receiveMsgs = append(receiveMsgs, nobletypes.NewMsgReceiveMessage(
@@ -123,31 +439,26 @@ func (n *Noble) attemptBroadcast(
 			msg.MsgSentBytes,
 			attestationBytes,
 		))
-
-		logger.Info(fmt.Sprintf(
-			"Broadcasting message from %d to %d: with source tx hash %s",
-			msg.SourceDomain,
-			msg.DestDomain,
-			msg.SourceTxHash))
-	}
-
-	if len(receiveMsgs) == 0 {
-		return nil
 	}
+	return receiveMsgs, nil
+}
 
+// signTx sets the msgs and gas limit on the tx builder, signs the tx with the account sequence and returns the encoded tx.
+func (n *Noble) signTx(
+	sdkContext sdkclient.Context,
+	txBuilder sdkclient.TxBuilder,
+	receiveMsgs []sdk.Msg,
+	accountSequence uint64,
+	gas uint64,
+) ([]byte, error) {
 	if err := txBuilder.SetMsgs(receiveMsgs...); err != nil {
-		return fmt.Errorf("failed to set messages on tx: %w", err)
+		return nil, fmt.Errorf("failed to set messages on tx: %w", err)
 	}
 
-	txBuilder.SetGasLimit(n.gasLimit)
+	txBuilder.SetGasLimit(gas)
 
 	txBuilder.SetMemo(n.txMemo)
 
-	n.mu.Lock()
-	defer n.mu.Unlock()
-
-	accountSequence := sequenceMap.Next(n.Domain())
-
 	sigV2 := signing.SignatureV2{
 		PubKey: n.privateKey.PubKey(),
 		Data: &signing.SingleSignatureData{
@@ -165,7 +476,7 @@ func (n *Noble) attemptBroadcast(
 
 	err := txBuilder.SetSignatures(sigV2)
 	if err != nil {
-		return fmt.Errorf("failed to set signatures: %w", err)
+		return nil, fmt.Errorf("failed to set signatures: %w", err)
 	}
 
 	sigV2, err = clientTx.SignWithPrivKey(
@@ -177,43 +488,29 @@ func (n *Noble) attemptBroadcast(
 		accountSequence,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to sign tx: %w", err)
+		return nil, fmt.Errorf("failed to sign tx: %w", err)
 	}
 
 	if err := txBuilder.SetSignatures(sigV2); err != nil {
-		return fmt.Errorf("failed to set signatures: %w", err)
+		return nil, fmt.Errorf("failed to set signatures: %w", err)
 	}
 
 	// Generated Protobuf-encoded bytes.
 	txBytes, err := sdkContext.TxConfig.TxEncoder()(txBuilder.GetTx())
 	if err != nil {
-		return fmt.Errorf("failed to proto encode tx: %w", err)
-	}
-
-	rpcResponse, err := n.cc.RPCClient.BroadcastTxSync(ctx, txBytes)
-	if err != nil {
-		return err
-	}
-
-	if rpcResponse.Code == 32 {
-		newAccountSequence := n.extractAccountSequence(ctx, logger, rpcResponse.Log)
-		logger.Debug(fmt.Sprintf("retrying with new account sequence: %d", newAccountSequence))
-		sequenceMap.Put(n.Domain(), newAccountSequence)
-	}
-
-	if rpcResponse.Code != 0 {
-		return fmt.Errorf("received non-zero: %d - %s", rpcResponse.Code, rpcResponse.Log)
+		return nil, fmt.Errorf("failed to proto encode tx: %w", err)
 	}
+	return txBytes, nil
+}
 
-	// Tx was successfully broadcast
-	for _, msg := range msgs {
-		msg.DestTxHash = rpcResponse.Hash.String()
-		msg.Status = types.Complete
+// newSDKContext returns a client context able to encode and sign cctp txs
+func newSDKContext() sdkclient.Context {
+	interfaceRegistry := codectypes.NewInterfaceRegistry()
+	nobletypes.RegisterInterfaces(interfaceRegistry)
+	cdc := codec.NewProtoCodec(interfaceRegistry)
+	return sdkclient.Context{
+		TxConfig: xauthtx.NewTxConfig(cdc, xauthtx.DefaultSignModes),
 	}
-
-	logger.Info(fmt.Sprintf("Successfully broadcast %s to Noble.  Tx hash: %s", msgs[0].SourceTxHash, msgs[0].DestTxHash))
-
-	return nil
 }
 
 // extractAccountSequence attempts to extract the account sequence number from the RPC response logs when
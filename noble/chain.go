@@ -6,7 +6,9 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -16,11 +18,15 @@ import (
 	"cosmossdk.io/log"
 
 	"github.com/strangelove-ventures/noble-cctp-relayer/cosmos"
+	"github.com/strangelove-ventures/noble-cctp-relayer/relayer"
 	"github.com/strangelove-ventures/noble-cctp-relayer/types"
 )
 
 var _ types.Chain = (*Noble)(nil)
 
+// maxLatestBlockAge is how long the latest block height may go without increasing before the chain is reported unhealthy
+const maxLatestBlockAge = time.Minute
+
 type Noble struct {
 	// from config
 	chainID               string
@@ -32,6 +38,10 @@ type Noble struct {
 	lookbackPeriod        uint64
 	workers               uint32
 	gasLimit              uint64
+	gasAdjustment         float64
+	inclusionTimeout      time.Duration
+	batchWindow           time.Duration
+	batchMaxMsgs          int
 	txMemo                string
 	maxRetries            int
 	retryIntervalSeconds  int
@@ -40,10 +50,22 @@ type Noble struct {
 
 	mu sync.Mutex
 
+	// batcher collects the messages of concurrent broadcasts into a single tx, when batching is enabled
+	batcher     *relayer.Batcher[*types.MessageState]
+	batcherOnce sync.Once
+
+	// submitted holds the txs that were broadcast and not seen included yet
+	submitted *submittedTxs
+
 	cc *cosmos.CosmosProvider
 
 	latestBlock      uint64
 	lastFlushedBlock uint64
+
+	// latestBlockIncreased is when the latest block last increased
+	latestBlockIncreased time.Time
+
+	flushTrigger chan struct{}
 }
 
 func NewChain(
@@ -54,21 +76,36 @@ func NewChain(
 	lookbackPeriod uint64,
 	workers uint32,
 	gasLimit uint64,
+	gasAdjustment float64,
+	inclusionTimeout time.Duration,
+	batchWindow time.Duration,
+	batchMaxMsgs int,
 	txMemo string,
 	maxRetries int,
 	retryIntervalSeconds int,
 	blockQueueChannelSize uint64,
 	minAmount uint64,
 ) (*Noble, error) {
-	keyBz, err := hex.DecodeString(privateKey)
-	if err != nil {
-		return nil, fmt.Errorf("unable to parse noble private key: %w", err)
+	// a chain without a private key can only be queried
+	var privKey *secp256k1.PrivKey
+	var minterAddress string
+	if privateKey != "" {
+		var err error
+		privKey, err = parsePrivateKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		minterAddress = sdk.MustBech32ifyAddressBytes("noble", privKey.PubKey().Address())
+	}
+	if gasAdjustment == 0 {
+		gasAdjustment = defaultGasAdjustment
+	}
+	if inclusionTimeout == 0 {
+		inclusionTimeout = defaultInclusionTimeout
+	}
+	if batchMaxMsgs == 0 {
+		batchMaxMsgs = defaultBatchMaxMsgs
 	}
-
-	privKey := secp256k1.PrivKey{Key: keyBz}
-
-	address := privKey.PubKey().Address()
-	minterAddress := sdk.MustBech32ifyAddressBytes("noble", address)
 
 	return &Noble{
 		chainID:               chainID,
@@ -76,26 +113,36 @@ func NewChain(
 		startBlock:            startBlock,
 		lookbackPeriod:        lookbackPeriod,
 		workers:               workers,
-		privateKey:            &privKey,
+		privateKey:            privKey,
 		minterAddress:         minterAddress,
 		gasLimit:              gasLimit,
+		gasAdjustment:         gasAdjustment,
+		inclusionTimeout:      inclusionTimeout,
+		batchWindow:           batchWindow,
+		batchMaxMsgs:          batchMaxMsgs,
 		txMemo:                txMemo,
 		maxRetries:            maxRetries,
 		retryIntervalSeconds:  retryIntervalSeconds,
 		blockQueueChannelSize: blockQueueChannelSize,
 		minAmount:             minAmount,
+		submitted:             newSubmittedTxs(),
+		flushTrigger:          make(chan struct{}, 1),
 	}, nil
 }
 
 func (n *Noble) AccountInfo(ctx context.Context) (uint64, uint64, error) {
-	res, err := authtypes.NewQueryClient(n.cc).Account(ctx, &authtypes.QueryAccountRequest{
+	return n.accountInfo(ctx, n.cc)
+}
+
+func (n *Noble) accountInfo(ctx context.Context, cc *cosmos.CosmosProvider) (uint64, uint64, error) {
+	res, err := authtypes.NewQueryClient(cc).Account(ctx, &authtypes.QueryAccountRequest{
 		Address: n.minterAddress,
 	})
 	if err != nil {
 		return 0, 0, fmt.Errorf("unable to query account for noble: %w", err)
 	}
 	var acc authtypes.AccountI
-	if err := n.cc.Cdc.InterfaceRegistry.UnpackAny(res.Account, &acc); err != nil {
+	if err := cc.Cdc.InterfaceRegistry.UnpackAny(res.Account, &acc); err != nil {
 		return 0, 0, fmt.Errorf("unable to unpack account for noble: %w", err)
 	}
 
@@ -119,10 +166,81 @@ func (n *Noble) LatestBlock() uint64 {
 
 func (n *Noble) SetLatestBlock(block uint64) {
 	n.mu.Lock()
+	if block > n.latestBlock {
+		n.latestBlockIncreased = time.Now()
+	}
 	n.latestBlock = block
 	n.mu.Unlock()
 }
 
+// CheckFees always succeeds, mints on Noble pay no fees.
+func (n *Noble) CheckFees(_ context.Context) error {
+	return nil
+}
+
+// CheckHealth ensures the latest block height is advancing and the rpc node is not catching up.
+func (n *Noble) CheckHealth(ctx context.Context) error {
+	n.mu.Lock()
+	latestBlock, increased := n.latestBlock, n.latestBlockIncreased
+	n.mu.Unlock()
+
+	if latestBlock == 0 {
+		return errors.New("latest block height not queried yet")
+	}
+	if age := time.Since(increased); age > maxLatestBlockAge {
+		return fmt.Errorf("latest block %d has not increased in %s", latestBlock, age.Round(time.Second))
+	}
+
+	res, err := n.cc.RPCClient.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to query rpc status: %w", err)
+	}
+	if res.SyncInfo.CatchingUp {
+		return errors.New("rpc node is catching up")
+	}
+	return nil
+}
+
+// NonceUsed queries the cctp module for whether the message with the source domain and nonce was received.
+func (n *Noble) NonceUsed(ctx context.Context, sourceDomain types.Domain, nonce uint64) (bool, error) {
+	return n.cc.QueryUsedNonce(ctx, sourceDomain, nonce)
+}
+
+// CheckConfig ensures the rpc endpoint serves the configured chain id and the minter account exists.
+// Minting on Noble is free, so no gas balance is required.
+func (n *Noble) CheckConfig(ctx context.Context) []types.ConfigCheck {
+	name := n.Name()
+	checks := []types.ConfigCheck{types.NewConfigCheck(name, "minter key", n.minterAddress, nil)}
+
+	cc, err := cosmos.NewProvider(n.rpcURL)
+	if err == nil {
+		err = n.checkChainID(ctx, cc)
+	}
+	checks = append(checks, types.NewConfigCheck(name, "rpc", n.rpcURL, err))
+	if cc == nil {
+		return append(checks, types.NewConfigCheck(name, "minter account", "", errors.New("rpc endpoint unavailable")))
+	}
+
+	var value string
+	accountNumber, _, err := n.accountInfo(ctx, cc)
+	if err == nil {
+		value = fmt.Sprintf("account number %d", accountNumber)
+	}
+	return append(checks, types.NewConfigCheck(name, "minter account", value, err))
+}
+
+// checkChainID ensures the rpc endpoint serves the configured chain id.
+func (n *Noble) checkChainID(ctx context.Context, cc *cosmos.CosmosProvider) error {
+	res, err := cc.RPCClient.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to query rpc status: %w", err)
+	}
+	if res.NodeInfo.Network != n.chainID {
+		return fmt.Errorf("endpoint serves chain id %s, expected %s", res.NodeInfo.Network, n.chainID)
+	}
+	return nil
+}
+
 func (n *Noble) LastFlushedBlock() uint64 {
 	return n.lastFlushedBlock
 }
@@ -164,6 +282,36 @@ func (n *Noble) InitializeClients(ctx context.Context, logger log.Logger) error
 	return nil
 }
 
+func (n *Noble) TriggerFlush() {
+	select {
+	case n.flushTrigger <- struct{}{}:
+	default:
+	}
+}
+
+// QueryTxMessages fetches a source tx by its hash and parses the CCTP MessageSent events it emitted.
+func (n *Noble) QueryTxMessages(ctx context.Context, logger log.Logger, txHash string) (*types.TxState, error) {
+	hash, err := hex.DecodeString(strings.TrimPrefix(txHash, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode tx hash %s: %w", txHash, err)
+	}
+
+	res, err := n.cc.RPCClient.Tx(ctx, hash, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query tx %s: %w", txHash, err)
+	}
+
+	parsedMsgs, err := txToMessageState(res)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsedMsgs) == 0 {
+		return nil, fmt.Errorf("no CCTP messages found in tx %s", txHash)
+	}
+
+	return &types.TxState{TxHash: res.Hash.String(), Msgs: parsedMsgs}, nil
+}
+
 func (n *Noble) CloseClients() error {
 	if n.cc != nil && n.cc.RPCClient.IsRunning() {
 		err := n.cc.RPCClient.Stop()
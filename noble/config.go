@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/strangelove-ventures/noble-cctp-relayer/types"
 )
@@ -13,6 +14,9 @@ var _ types.ChainConfig = (*ChainConfig)(nil)
 const defaultBlockQueueChannelSize = 1000000
 
 type ChainConfig struct {
+	// Preset references a built-in chain preset, ex: "mainnet/noble". Fields set in the config override the preset
+	Preset string `yaml:"preset,omitempty"`
+
 	RPC     string `yaml:"rpc"`
 	ChainID string `yaml:"chain-id"`
 
@@ -25,6 +29,16 @@ type ChainConfig struct {
 	BroadcastRetries       int    `yaml:"broadcast-retries"`
 	BroadcastRetryInterval int    `yaml:"broadcast-retry-interval"`
 
+	// GasAdjustment scales the simulated gas of a tx into its gas limit, capped at GasLimit. Defaults to 1.5
+	GasAdjustment float64 `yaml:"gas-adjustment"`
+	// InclusionTimeout is how long to wait for a broadcast tx to be included in a block before it is retried. Defaults to 1m
+	InclusionTimeout time.Duration `yaml:"inclusion-timeout"`
+
+	// BatchWindow is how long attested messages of different txs are collected to be minted in a single tx. 0 disables batching
+	BatchWindow time.Duration `yaml:"batch-window"`
+	// BatchMaxMsgs is the max number of messages in a batch, it is minted without waiting for the window once full. Defaults to 50
+	BatchMaxMsgs int `yaml:"batch-max-msgs"`
+
 	BlockQueueChannelSize uint64 `yaml:"block-queue-channel-size"`
 
 	MinMintAmount uint64 `yaml:"min-mint-amount"`
@@ -32,26 +46,40 @@ type ChainConfig struct {
 	MinterPrivateKey string `yaml:"minter-private-key"`
 }
 
+// ApplyPreset sets the chain's known values from the preset.
+func (c *ChainConfig) ApplyPreset(preset types.ChainPreset) {
+	c.ChainID = preset.CosmosChainID
+	c.LookbackPeriod = preset.LookbackPeriod
+}
+
 func (c *ChainConfig) Chain(name string) (types.Chain, error) {
-	envKey := strings.ToUpper(name) + "_PRIV_KEY"
-	privKey := os.Getenv(envKey)
-
-	if len(c.MinterPrivateKey) == 0 || len(privKey) != 0 {
-		if len(privKey) == 0 {
-			return nil, fmt.Errorf("env variable %s is empty, priv key not found for chain %s", envKey, name)
-		} else {
-			c.MinterPrivateKey = privKey
-		}
+	privKey, _, err := c.minterPrivateKey(name)
+	if err != nil {
+		return nil, err
 	}
+	c.MinterPrivateKey = privKey
+
+	return c.newChain(c.MinterPrivateKey)
+}
+
+// QueryChain creates the chain without the minter private key.
+func (c *ChainConfig) QueryChain(_ string) (types.Chain, error) {
+	return c.newChain("")
+}
 
+func (c *ChainConfig) newChain(privKey string) (types.Chain, error) {
 	return NewChain(
 		c.RPC,
 		c.ChainID,
-		c.MinterPrivateKey,
+		privKey,
 		c.StartBlock,
 		c.LookbackPeriod,
 		c.Workers,
 		c.GasLimit,
+		c.GasAdjustment,
+		c.InclusionTimeout,
+		c.BatchWindow,
+		c.BatchMaxMsgs,
 		c.TxMemo,
 		c.BroadcastRetries,
 		c.BroadcastRetryInterval,
@@ -59,3 +87,29 @@ func (c *ChainConfig) Chain(name string) (types.Chain, error) {
 		c.MinMintAmount,
 	)
 }
+
+// MinterAddress resolves the minter private key the same way as Chain and returns its address
+// along with where the key was found.
+func (c *ChainConfig) MinterAddress(name string) (address string, source string, err error) {
+	privKey, source, err := c.minterPrivateKey(name)
+	if err != nil {
+		return "", "", err
+	}
+	address, err = MinterAddress(privKey)
+	if err != nil {
+		return "", "", err
+	}
+	return address, source, nil
+}
+
+// minterPrivateKey returns the minter private key, preferring the <NAME>_PRIV_KEY env variable over the config.
+func (c *ChainConfig) minterPrivateKey(name string) (privKey string, source string, err error) {
+	envKey := strings.ToUpper(name) + "_PRIV_KEY"
+	if privKey := os.Getenv(envKey); len(privKey) != 0 {
+		return privKey, "env " + envKey, nil
+	}
+	if len(c.MinterPrivateKey) == 0 {
+		return "", "", fmt.Errorf("env variable %s is empty, priv key not found for chain %s", envKey, name)
+	}
+	return c.MinterPrivateKey, "config", nil
+}
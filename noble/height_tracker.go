@@ -0,0 +1,40 @@
+package noble
+
+import (
+	"sync"
+)
+
+// heightTracker tracks block heights that are processed out of order by the listener workers
+// and reports the highest height below which every block has been processed.
+type heightTracker struct {
+	mu        sync.Mutex
+	next      uint64
+	processed map[uint64]bool
+}
+
+// newHeightTracker returns a heightTracker expecting start to be the first height processed.
+func newHeightTracker(start uint64) *heightTracker {
+	return &heightTracker{
+		next:      start,
+		processed: make(map[uint64]bool),
+	}
+}
+
+// markProcessed records that the height has been processed. If this completes a contiguous run of heights,
+// the highest height of that run is returned with ok set to true. Heights that were already passed are ignored.
+func (t *heightTracker) markProcessed(height uint64) (processed uint64, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if height < t.next {
+		return 0, false
+	}
+	t.processed[height] = true
+
+	for t.processed[t.next] {
+		delete(t.processed, t.next)
+		t.next++
+		ok = true
+	}
+	return t.next - 1, ok
+}
@@ -0,0 +1,28 @@
+package noble
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeightTracker(t *testing.T) {
+	tracker := newHeightTracker(100)
+
+	// out of order heights do not advance past a gap
+	_, ok := tracker.markProcessed(102)
+	require.False(t, ok)
+
+	processed, ok := tracker.markProcessed(100)
+	require.True(t, ok)
+	require.Equal(t, uint64(100), processed)
+
+	// filling the gap advances through the already processed height
+	processed, ok = tracker.markProcessed(101)
+	require.True(t, ok)
+	require.Equal(t, uint64(102), processed)
+
+	// heights re-queued by a flush are ignored
+	_, ok = tracker.markProcessed(50)
+	require.False(t, ok)
+}
@@ -0,0 +1,37 @@
+package noble
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// parsePrivateKey parses a hex encoded secp256k1 private key
+func parsePrivateKey(privateKey string) (*secp256k1.PrivKey, error) {
+	keyBz, err := hex.DecodeString(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse noble private key: %w", err)
+	}
+	return &secp256k1.PrivKey{Key: keyBz}, nil
+}
+
+// MinterAddress returns the noble bech32 address of a hex encoded private key
+func MinterAddress(privateKey string) (string, error) {
+	privKey, err := parsePrivateKey(privateKey)
+	if err != nil {
+		return "", err
+	}
+	return sdk.Bech32ifyAddressBytes("noble", privKey.PubKey().Address())
+}
+
+// GenerateKey returns a new hex encoded private key and its noble address
+func GenerateKey() (privateKey string, address string, err error) {
+	privKey := secp256k1.GenPrivKey()
+	address, err = sdk.Bech32ifyAddressBytes("noble", privKey.PubKey().Address())
+	if err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(privKey.Key), address, nil
+}
@@ -0,0 +1,21 @@
+package noble_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/noble"
+)
+
+func TestGenerateKey(t *testing.T) {
+	privKey, addr, err := noble.GenerateKey()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(addr, "noble1"))
+
+	// the generated key is in the format expected by the config
+	derived, err := noble.MinterAddress(privKey)
+	require.NoError(t, err)
+	require.Equal(t, addr, derived)
+}
@@ -2,7 +2,9 @@ package noble
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"cosmossdk.io/log"
@@ -17,6 +19,7 @@ func (n *Noble) StartListener(
 	ctx context.Context,
 	logger log.Logger,
 	processingQueue chan *types.TxState,
+	checkpoints *types.Checkpoints,
 	flushOnlyMode bool,
 	flushInterval_ time.Duration,
 ) {
@@ -25,7 +28,12 @@ func (n *Noble) StartListener(
 	flushInterval = flushInterval_
 
 	if n.startBlock == 0 {
-		n.startBlock = n.LatestBlock()
+		if checkpoint, ok := checkpoints.Load(n.Name()); ok {
+			logger.Info(fmt.Sprintf("Resuming from checkpoint at block %d", checkpoint))
+			n.startBlock = checkpoint
+		} else {
+			n.startBlock = n.LatestBlock()
+		}
 	}
 
 	logger.Info(fmt.Sprintf("Starting Noble listener at block %d looking back %d blocks",
@@ -50,8 +58,35 @@ func (n *Noble) StartListener(
 	blockQueue := make(chan uint64, n.blockQueueChannelSize)
 
 	if !flushOnlyMode {
-		// history
 		currentBlock -= lookback
+	}
+	checkpoints.Set(n.Name(), currentBlock)
+	tracker := newHeightTracker(currentBlock)
+
+	// constantly query for blocks
+	for i := 0; i < int(n.workers); i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case block := <-blockQueue:
+					if err := n.consumeBlock(ctx, logger, block, processingQueue, checkpoints); err != nil {
+						logger.Debug(fmt.Sprintf("Unable to query Noble block %d. Will retry.", block), "error:", err)
+						blockQueue <- block
+						continue
+					}
+
+					if processed, ok := tracker.markProcessed(block); ok {
+						checkpoints.Advance(n.Name(), processed)
+					}
+				}
+			}
+		}()
+	}
+
+	if !flushOnlyMode {
+		// history
 		for currentBlock <= chainTip {
 			blockQueue <- currentBlock
 			currentBlock++
@@ -86,42 +121,91 @@ func (n *Noble) StartListener(
 		}()
 	}
 
-	// constantly query for blocks
-	for i := 0; i < int(n.workers); i++ {
-		go func() {
-			for {
-				select {
-				case <-ctx.Done():
-					return
-				case block := <-blockQueue:
-					res, err := n.cc.RPCClient.TxSearch(ctx, fmt.Sprintf("tx.height=%d", block), false, nil, nil, "")
-					if err != nil || res == nil {
-						logger.Debug(fmt.Sprintf("Unable to query Noble block %d. Will retry.", block), "error:", err)
-						blockQueue <- block
-						continue
-					}
+	// the flush mechanism also serves manually triggered flushes when no flush interval is set
+	go n.flushMechanism(ctx, logger, blockQueue, flushOnlyMode)
+
+	<-ctx.Done()
+}
+
+// consumeBlock queries the txs of a block and places them on the processing queue.
+// If checkpoints is set, each tx is held in it until the processor persists it.
+func (n *Noble) consumeBlock(
+	ctx context.Context,
+	logger log.Logger,
+	block uint64,
+	processingQueue chan *types.TxState,
+	checkpoints *types.Checkpoints,
+) error {
+	res, err := n.cc.RPCClient.TxSearch(ctx, fmt.Sprintf("tx.height=%d", block), false, nil, nil, "")
+	if err != nil {
+		return err
+	}
+	if res == nil {
+		return errors.New("empty tx search response")
+	}
+
+	for _, tx := range res.Txs {
+		parsedMsgs, err := txToMessageState(tx)
+		if err != nil {
+			logger.Error("Unable to parse Noble log to message state", "err", err.Error())
+			continue
+		}
+		for _, parsedMsg := range parsedMsgs {
+			logger.Info(fmt.Sprintf("New stream msg with nonce %d from %d with tx hash %s", parsedMsg.Nonce, parsedMsg.SourceDomain, parsedMsg.SourceTxHash))
+		}
+		if checkpoints != nil {
+			checkpoints.Hold(n.Name(), tx.Hash.String(), block)
+		}
+		processingQueue <- &types.TxState{TxHash: tx.Hash.String(), Msgs: parsedMsgs}
+	}
+	return nil
+}
+
+// ConsumeHistory queries the blocks from start to end with the configured number of workers
+// and places their txs on the processing queue. Blocks that fail to be queried are retried.
+func (n *Noble) ConsumeHistory(
+	ctx context.Context,
+	logger log.Logger,
+	processingQueue chan *types.TxState,
+	start, end uint64,
+) error {
+	if start > end {
+		return fmt.Errorf("start block %d is greater than end block %d", start, end)
+	}
+	logger = logger.With("chain", n.Name(), "chain_id", n.chainID, "domain", n.Domain())
+
+	workers := int(n.workers)
+	if workers == 0 {
+		workers = 1
+	}
 
-					for _, tx := range res.Txs {
-						parsedMsgs, err := txToMessageState(tx)
-						if err != nil {
-							logger.Error("Unable to parse Noble log to message state", "err", err.Error())
-							continue
-						}
-						for _, parsedMsg := range parsedMsgs {
-							logger.Info(fmt.Sprintf("New stream msg with nonce %d from %d with tx hash %s", parsedMsg.Nonce, parsedMsg.SourceDomain, parsedMsg.SourceTxHash))
-						}
-						processingQueue <- &types.TxState{TxHash: tx.Hash.String(), Msgs: parsedMsgs}
+	blockQueue := make(chan uint64)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for block := range blockQueue {
+				for {
+					// the txs are not relayed by the listener's processor, so they do not hold back its checkpoint
+					err := n.consumeBlock(ctx, logger, block, processingQueue, nil)
+					if err == nil || ctx.Err() != nil {
+						break
 					}
+					logger.Debug(fmt.Sprintf("Unable to query Noble block %d. Will retry.", block), "error:", err)
+					time.Sleep(time.Second)
 				}
 			}
 		}()
 	}
 
-	if flushInterval > 0 {
-		go n.flushMechanism(ctx, logger, blockQueue, flushOnlyMode)
+	for block := start; block <= end && ctx.Err() == nil; block++ {
+		blockQueue <- block
 	}
+	close(blockQueue)
+	wg.Wait()
 
-	<-ctx.Done()
+	return ctx.Err()
 }
 
 // flushMechanism looks back over the chain history every specified flushInterval.
@@ -140,7 +224,11 @@ func (n *Noble) flushMechanism(
 	blockQueue chan uint64,
 	flushOnlyMode bool,
 ) {
-	logger.Info(fmt.Sprintf("Starting flush mechanism. Will flush every %v", flushInterval))
+	if flushInterval > 0 {
+		logger.Info(fmt.Sprintf("Starting flush mechanism. Will flush every %v", flushInterval))
+	} else {
+		logger.Info("Starting flush mechanism. Will only flush when triggered")
+	}
 
 	// extraFlushBlocks is used to add an extra space between latest height and last flushed block
 	// this setting should only be used for the secondary, flush only relayer
@@ -150,56 +238,83 @@ func (n *Noble) flushMechanism(
 	}
 
 	for {
+		// without a flush interval, the timer never fires and only triggered flushes run
+		var timerC <-chan time.Time
 		timer := time.NewTimer(flushInterval)
+		if flushInterval > 0 {
+			timerC = timer.C
+		}
 		select {
-		case <-timer.C:
-			latestBlock := n.LatestBlock()
+		case <-timerC:
+		case <-n.flushTrigger:
+			timer.Stop()
+			logger.Info("Flush triggered")
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
 
-			// test to see that the rpc is available before attempting flush
-			res, err := n.cc.RPCClient.Status(ctx)
-			if err != nil {
-				logger.Error(fmt.Sprintf("Skipping flush... error reaching out to rpc, will retry flush in %v", flushInterval))
-				continue
-			}
-			if res.SyncInfo.CatchingUp {
-				logger.Error(fmt.Sprintf("Skipping flush... rpc still catching, will retry flush in %v", flushInterval))
-				continue
-			}
+		latestBlock := n.LatestBlock()
 
-			// initialize first lastFlushedBlock if not set
-			if n.lastFlushedBlock == 0 {
-				n.lastFlushedBlock = latestBlock - (2*n.lookbackPeriod + extraFlushBlocks)
+		// test to see that the rpc is available before attempting flush
+		res, err := n.cc.RPCClient.Status(ctx)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Skipping flush... error reaching out to rpc, will retry flush in %v", flushInterval))
+			continue
+		}
+		if res.SyncInfo.CatchingUp {
+			logger.Error(fmt.Sprintf("Skipping flush... rpc still catching, will retry flush in %v", flushInterval))
+			continue
+		}
 
-				if latestBlock < n.lookbackPeriod {
-					n.lastFlushedBlock = 0
-				}
+		// initialize first lastFlushedBlock if not set
+		if n.lastFlushedBlock == 0 {
+			n.lastFlushedBlock = latestBlock - (2*n.lookbackPeriod + extraFlushBlocks)
+
+			if latestBlock < n.lookbackPeriod {
+				n.lastFlushedBlock = 0
 			}
+		}
 
-			// start from the last block it flushed
-			startBlock := n.lastFlushedBlock
+		// start from the last block it flushed
+		startBlock := n.lastFlushedBlock
 
-			// set finish block to be latestBlock - lookbackPeriod
-			finishBlock := latestBlock - (n.lookbackPeriod + extraFlushBlocks)
+		// set finish block to be latestBlock - lookbackPeriod
+		finishBlock := latestBlock - (n.lookbackPeriod + extraFlushBlocks)
 
-			if startBlock >= finishBlock {
-				logger.Debug("No new blocks to flush")
-				continue
-			}
+		if startBlock >= finishBlock {
+			logger.Debug("No new blocks to flush")
+			continue
+		}
 
-			logger.Info(fmt.Sprintf("Flush started from %d to %d (current height: %d, lookback period: %d)", startBlock, finishBlock, latestBlock, n.lookbackPeriod))
+		logger.Info(fmt.Sprintf("Flush started from %d to %d (current height: %d, lookback period: %d)", startBlock, finishBlock, latestBlock, n.lookbackPeriod))
 
-			for i := startBlock; i <= finishBlock; i++ {
-				blockQueue <- i
-			}
-			n.lastFlushedBlock = finishBlock
+		for i := startBlock; i <= finishBlock; i++ {
+			blockQueue <- i
+		}
+		n.lastFlushedBlock = finishBlock
 
-			logger.Info("Flush complete")
+		logger.Info("Flush complete")
+	}
+}
 
-		case <-ctx.Done():
-			timer.Stop()
-			return
-		}
+// QueryLatestBlock queries the latest block height from the rpc status.
+func (n *Noble) QueryLatestBlock(ctx context.Context) (uint64, error) {
+	res, err := n.cc.RPCClient.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(res.SyncInfo.LatestBlockHeight), nil
+}
+
+// BlockTime queries the time of the block header at the height.
+func (n *Noble) BlockTime(ctx context.Context, height uint64) (time.Time, error) {
+	h := int64(height)
+	res, err := n.cc.RPCClient.Block(ctx, &h)
+	if err != nil {
+		return time.Time{}, err
 	}
+	return res.Block.Time, nil
 }
 
 func (n *Noble) TrackLatestBlockHeight(ctx context.Context, logger log.Logger, m *relayer.PromMetrics) {
@@ -209,13 +324,13 @@ func (n *Noble) TrackLatestBlockHeight(ctx context.Context, logger log.Logger, m
 
 	// inner function to update block height
 	updateBlockHeight := func() {
-		res, err := n.cc.RPCClient.Status(ctx)
+		res, err := n.QueryLatestBlock(ctx)
 		if err != nil {
 			logger.Error("Unable to query Nobles latest height", "err", err)
 		} else {
-			n.SetLatestBlock(uint64(res.SyncInfo.LatestBlockHeight))
+			n.SetLatestBlock(res)
 			if m != nil {
-				m.SetLatestHeight(n.Name(), d, res.SyncInfo.LatestBlockHeight)
+				m.SetLatestHeight(n.Name(), d, int64(res))
 			}
 		}
 	}
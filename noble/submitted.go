@@ -0,0 +1,139 @@
+package noble
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"cosmossdk.io/log"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+// maxUnconfirmedTxs is the number of mempool txs listed when looking for a submitted tx
+const maxUnconfirmedTxs = 100
+
+// submittedTx is a tx that passed CheckTx and has not been seen included yet
+type submittedTx struct {
+	hash     []byte
+	sequence uint64
+}
+
+// submittedTxs tracks the tx each message was last submitted in, until the tx is included or can no longer be.
+// A message whose tx may still be in the mempool is not signed into another tx.
+type submittedTxs struct {
+	mu sync.Mutex
+	// map "<source domain>-<nonce>" -> tx the message was last submitted in
+	txs map[string]*submittedTx
+}
+
+func newSubmittedTxs() *submittedTxs {
+	return &submittedTxs{txs: make(map[string]*submittedTx)}
+}
+
+func submittedKey(msg *types.MessageState) string {
+	return fmt.Sprintf("%d-%d", msg.SourceDomain, msg.Nonce)
+}
+
+// track records the tx as the last one the msgs were submitted in
+func (s *submittedTxs) track(msgs []*types.MessageState, tx *submittedTx) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, msg := range msgs {
+		s.txs[submittedKey(msg)] = tx
+	}
+}
+
+// remove stops tracking the tx for every message it was submitted for
+func (s *submittedTxs) remove(tx *submittedTx) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, submitted := range s.txs {
+		if submitted == tx {
+			delete(s.txs, key)
+		}
+	}
+}
+
+// find returns the distinct txs the msgs were last submitted in
+func (s *submittedTxs) find(msgs []*types.MessageState) []*submittedTx {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found []*submittedTx
+	for _, msg := range msgs {
+		tx, ok := s.txs[submittedKey(msg)]
+		if ok && !containsTx(found, tx) {
+			found = append(found, tx)
+		}
+	}
+	return found
+}
+
+func containsTx(txs []*submittedTx, tx *submittedTx) bool {
+	for _, t := range txs {
+		if t == tx {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSubmitted checks the earlier txs of the msgs that were not seen included. Txs that were included, or whose
+// account sequence was used by another tx, are forgotten. Txs evicted from the mempool are forgotten and their account
+// sequence is released. An error wrapping errNotIncluded is returned while one of them is still in the mempool, so
+// that the msgs are not signed into another tx.
+func (n *Noble) checkSubmitted(
+	ctx context.Context,
+	logger log.Logger,
+	msgs []*types.MessageState,
+	sequenceMap *types.SequenceMap,
+) error {
+	for _, tx := range n.submitted.find(msgs) {
+		if res, err := n.cc.RPCClient.Tx(ctx, tx.hash, false); err == nil {
+			// the msgs the tx minted are marked complete by their used nonce
+			logger.Info(fmt.Sprintf("Earlier tx %X was included in block %d with code %d", tx.hash, res.Height, res.TxResult.Code))
+			n.submitted.remove(tx)
+			continue
+		}
+
+		_, accountSequence, err := n.AccountInfo(ctx)
+		if err != nil {
+			return err
+		}
+		if accountSequence > tx.sequence {
+			// another tx used the account sequence, so the earlier tx can no longer be included
+			n.submitted.remove(tx)
+			continue
+		}
+
+		inMempool, err := n.inMempool(ctx, tx.hash)
+		if err != nil {
+			return err
+		}
+		if inMempool {
+			return fmt.Errorf("%w: earlier tx %X is still in the mempool", errNotIncluded, tx.hash)
+		}
+
+		logger.Info(fmt.Sprintf("Earlier tx %X was evicted from the mempool, signing a new tx", tx.hash))
+		n.submitted.remove(tx)
+		sequenceMap.Release(n.Domain(), tx.sequence)
+	}
+	return nil
+}
+
+// inMempool reports whether the tx is in the node's mempool. If the mempool holds more txs than are listed, a tx that
+// is not listed may still be in it, so it is reported as in the mempool.
+func (n *Noble) inMempool(ctx context.Context, hash []byte) (bool, error) {
+	limit := maxUnconfirmedTxs
+	res, err := n.cc.RPCClient.UnconfirmedTxs(ctx, &limit)
+	if err != nil {
+		return false, fmt.Errorf("unable to query the mempool: %w", err)
+	}
+	for _, tx := range res.Txs {
+		if bytes.Equal(tx.Hash(), hash) {
+			return true, nil
+		}
+	}
+	return res.Count < res.Total, nil
+}
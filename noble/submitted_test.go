@@ -0,0 +1,32 @@
+package noble
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+func TestSubmittedTxs(t *testing.T) {
+	s := newSubmittedTxs()
+	msg1 := &types.MessageState{SourceDomain: 0, Nonce: 1}
+	msg2 := &types.MessageState{SourceDomain: 0, Nonce: 2}
+	msg3 := &types.MessageState{SourceDomain: 1, Nonce: 1}
+
+	batch := &submittedTx{hash: []byte{1}, sequence: 10}
+	s.track([]*types.MessageState{msg1, msg2}, batch)
+	require.Empty(t, s.find([]*types.MessageState{msg3}))
+
+	// a tx is returned once, however many of the msgs it was submitted for
+	require.Equal(t, []*submittedTx{batch}, s.find([]*types.MessageState{msg1, msg2, msg3}))
+
+	// a msg submitted again is tracked by its latest tx
+	single := &submittedTx{hash: []byte{2}, sequence: 11}
+	s.track([]*types.MessageState{msg2}, single)
+	require.Equal(t, []*submittedTx{single}, s.find([]*types.MessageState{msg2}))
+
+	s.remove(batch)
+	require.Empty(t, s.find([]*types.MessageState{msg1}))
+	require.Equal(t, []*submittedTx{single}, s.find([]*types.MessageState{msg1, msg2}))
+}
@@ -0,0 +1,79 @@
+package relayer
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Batcher collects items submitted concurrently and flushes them together, once the window has passed since the
+// first item of a batch or once the batch reaches the max size.
+type Batcher[T any] struct {
+	ctx     context.Context
+	window  time.Duration
+	maxSize int
+	flush   func(ctx context.Context, items []T) error
+
+	mu      sync.Mutex
+	pending *batch[T]
+}
+
+// batch is a set of items flushed together. done is closed once it was flushed.
+type batch[T any] struct {
+	items []T
+	timer *time.Timer
+	done  chan struct{}
+	err   error
+}
+
+// NewBatcher returns a Batcher that passes its batches to flush. A maxSize of 0 does not limit the batch size.
+// Batches are flushed with ctx rather than the context of a submitter, since a batch holds the items of many submitters.
+func NewBatcher[T any](ctx context.Context, window time.Duration, maxSize int, flush func(ctx context.Context, items []T) error) *Batcher[T] {
+	return &Batcher[T]{
+		ctx:     ctx,
+		window:  window,
+		maxSize: maxSize,
+		flush:   flush,
+	}
+}
+
+// Submit adds the items to the pending batch and blocks until the batch was flushed, returning the flush's error.
+// If ctx is done first, Submit returns its error, but the items are still flushed with the batch.
+func (b *Batcher[T]) Submit(ctx context.Context, items ...T) error {
+	b.mu.Lock()
+	p := b.pending
+	if p == nil {
+		p = &batch[T]{done: make(chan struct{})}
+		p.timer = time.AfterFunc(b.window, func() {
+			b.mu.Lock()
+			// the batch may have been flushed for reaching the max size meanwhile
+			if b.pending != p {
+				b.mu.Unlock()
+				return
+			}
+			b.pending = nil
+			b.mu.Unlock()
+			b.run(p)
+		})
+		b.pending = p
+	}
+	p.items = append(p.items, items...)
+	if b.maxSize > 0 && len(p.items) >= b.maxSize {
+		b.pending = nil
+		p.timer.Stop()
+		go b.run(p)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return p.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *Batcher[T]) run(p *batch[T]) {
+	p.err = b.flush(b.ctx, p.items)
+	close(p.done)
+}
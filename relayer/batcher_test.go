@@ -0,0 +1,72 @@
+package relayer_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/relayer"
+)
+
+func TestBatcher(t *testing.T) {
+	var mu sync.Mutex
+	var flushed [][]int
+	batcher := relayer.NewBatcher(context.Background(), 100*time.Millisecond, 4, func(_ context.Context, items []int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		flushed = append(flushed, items)
+		return nil
+	})
+
+	// items submitted within the window are flushed together
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, batcher.Submit(context.Background(), i))
+		}(i)
+	}
+	wg.Wait()
+	require.Len(t, flushed, 1)
+	require.ElementsMatch(t, []int{0, 1, 2}, flushed[0])
+
+	// a full batch is flushed without waiting for the window
+	start := time.Now()
+	require.NoError(t, batcher.Submit(context.Background(), 3, 4, 5, 6))
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+	require.Len(t, flushed, 2)
+	require.Equal(t, []int{3, 4, 5, 6}, flushed[1])
+}
+
+func TestBatcherError(t *testing.T) {
+	flushErr := errors.New("flush failed")
+	batcher := relayer.NewBatcher(context.Background(), 10*time.Millisecond, 0, func(_ context.Context, _ []string) error {
+		return flushErr
+	})
+
+	require.ErrorIs(t, batcher.Submit(context.Background(), "a"), flushErr)
+}
+
+func TestBatcherSubmitterCancelled(t *testing.T) {
+	batcher := relayer.NewBatcher(context.Background(), 50*time.Millisecond, 0, func(ctx context.Context, _ []int) error {
+		return ctx.Err()
+	})
+
+	// the submitter that started the batch gives up, the batch is still flushed for the others
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- batcher.Submit(ctx, 1)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	require.NoError(t, batcher.Submit(context.Background(), 2))
+}
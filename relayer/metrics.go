@@ -14,6 +14,8 @@ type PromMetrics struct {
 	WalletBalance   *prometheus.GaugeVec
 	LatestHeight    *prometheus.GaugeVec
 	BroadcastErrors *prometheus.CounterVec
+	Unminted        *prometheus.GaugeVec
+	FeeHeld         *prometheus.GaugeVec
 }
 
 func InitPromMetrics(port int16) *PromMetrics {
@@ -24,6 +26,8 @@ func InitPromMetrics(port int16) *PromMetrics {
 		walletLabels         = []string{"chain", "address", "denom"}
 		heightLabels         = []string{"chain", "domain"}
 		broadcastErrorLabels = []string{"chain", "domain"}
+		unmintedLabels       = []string{"chain", "source_domain", "dest_domain"}
+		feeHeldLabels        = []string{"chain", "domain"}
 	)
 
 	m := &PromMetrics{
@@ -39,11 +43,21 @@ func InitPromMetrics(port int16) *PromMetrics {
 			Name: "cctp_relayer_broadcast_errors_total",
 			Help: "The total number of failed broadcasts. Note: this is AFTER is retires `broadcast-retries` number of times (config setting).",
 		}, broadcastErrorLabels),
+		Unminted: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cctp_relayer_unminted_messages",
+			Help: "The number of burns older than the reconcile threshold with no matching mint, as of the last reconciliation",
+		}, unmintedLabels),
+		FeeHeld: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cctp_relayer_fee_held_messages",
+			Help: "The number of attested messages waiting for the network fee of their destination chain to drop below its cap",
+		}, feeHeldLabels),
 	}
 
 	reg.MustRegister(m.WalletBalance)
 	reg.MustRegister(m.LatestHeight)
 	reg.MustRegister(m.BroadcastErrors)
+	reg.MustRegister(m.Unminted)
+	reg.MustRegister(m.FeeHeld)
 
 	// Expose /metrics HTTP endpoint
 	go func() {
@@ -69,3 +83,15 @@ func (m *PromMetrics) SetLatestHeight(chain, domain string, height int64) {
 func (m *PromMetrics) IncBroadcastErrors(chain, domain string) {
 	m.BroadcastErrors.WithLabelValues(chain, domain).Inc()
 }
+
+// SetUnminted replaces the unminted message counts of a source chain, keyed by destination domain.
+func (m *PromMetrics) SetUnminted(chain, sourceDomain string, counts map[string]int) {
+	m.Unminted.DeletePartialMatch(prometheus.Labels{"chain": chain})
+	for destDomain, count := range counts {
+		m.Unminted.WithLabelValues(chain, sourceDomain, destDomain).Set(float64(count))
+	}
+}
+
+func (m *PromMetrics) SetFeeHeld(chain, domain string, count int) {
+	m.FeeHeld.WithLabelValues(chain, domain).Set(float64(count))
+}
@@ -0,0 +1,128 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+// maxArchiveLineSize bounds the size of a single archived TxState when scanning the archive
+const maxArchiveLineSize = 16 * 1024 * 1024
+
+// ArchivedTxState is a single line of the archive file.
+type ArchivedTxState struct {
+	ArchivedAt time.Time      `json:"archived_at"`
+	TxState    *types.TxState `json:"tx_state"`
+}
+
+// Archive is an append-only JSONL file of TxStates that were evicted from the State.
+// The offset of each tx hash's most recent entry is indexed in memory, so lookups read a single line.
+type Archive struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+
+	// index maps source tx hash -> offset of its most recent entry
+	index map[string]int64
+	// size is the offset the next entry is written at
+	size int64
+}
+
+// NewArchive opens (or creates) the archive file at path for appending, and indexes the entries already in it.
+func NewArchive(path string) (*Archive, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open archive file %s: %w", path, err)
+	}
+	a := &Archive{path: path, file: f, index: make(map[string]int64)}
+	if err := a.buildIndex(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to index archive file %s: %w", path, err)
+	}
+	return a, nil
+}
+
+// buildIndex scans the archive once and records the offset of each tx hash's most recent entry.
+func (a *Archive) buildIndex() error {
+	if _, err := a.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(a.file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var entry ArchivedTxState
+			// a partially written line (ex: crash during append) should not hide the rest of the archive
+			if json.Unmarshal(line, &entry) == nil && entry.TxState != nil {
+				a.index[entry.TxState.TxHash] = offset
+			}
+			offset += int64(len(line))
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	a.size = offset
+	return nil
+}
+
+// Append writes the TxState to the end of the archive.
+func (a *Archive) Append(tx *types.TxState) error {
+	bz, err := json.Marshal(ArchivedTxState{ArchivedAt: time.Now(), TxState: tx})
+	if err != nil {
+		return fmt.Errorf("unable to marshal tx state %s: %w", tx.TxHash, err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	n, err := a.file.Write(append(bz, '\n'))
+	if err != nil {
+		return err
+	}
+	a.index[tx.TxHash] = a.size
+	a.size += int64(n)
+	return nil
+}
+
+// Find returns the most recently archived entry of the source tx hash from the index.
+// It returns nil, without reading the archive, if the tx hash was never archived.
+func (a *Archive) Find(txHash string) (*ArchivedTxState, error) {
+	a.mu.Lock()
+	offset, ok := a.index[txHash]
+	a.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	scanner := bufio.NewScanner(io.NewSectionReader(a.file, offset, maxArchiveLineSize))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxArchiveLineSize)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("archived entry of %s not found at offset %d", txHash, offset)
+	}
+	var entry ArchivedTxState
+	if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal archived entry of %s: %w", txHash, err)
+	}
+	return &entry, nil
+}
+
+func (a *Archive) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
@@ -0,0 +1,140 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+var (
+	_ types.StateStore      = (*BoltStore)(nil)
+	_ types.CheckpointStore = (*BoltStore)(nil)
+	_ types.DeadLetterStore = (*BoltStore)(nil)
+)
+
+var (
+	txStateBucket    = []byte("tx_states")
+	checkpointBucket = []byte("checkpoints")
+	deadLetterBucket = []byte("dead_letters")
+)
+
+// BoltStore is a StateStore backed by an embedded bbolt key/value file.
+// TxStates and dead letters are JSON encoded and keyed by their source tx hash. Block checkpoints are keyed by chain name.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (or creates) the bbolt database at path.
+func NewBoltStore(path string) (*BoltStore, error) {
+	// the timeout prevents hanging forever if another relayer process holds the file lock
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open state db at %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{txStateBucket, checkpointBucket, deadLetterBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to initialize state db buckets: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (s *BoltStore) Put(txHash string, state *types.TxState) error {
+	bz, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("unable to marshal tx state %s: %w", txHash, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(txStateBucket).Put([]byte(txHash), bz)
+	})
+}
+
+func (s *BoltStore) Delete(txHash string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(txStateBucket).Delete([]byte(txHash))
+	})
+}
+
+func (s *BoltStore) All() ([]*types.TxState, error) {
+	var states []*types.TxState
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(txStateBucket).ForEach(func(k, v []byte) error {
+			var state types.TxState
+			if err := json.Unmarshal(v, &state); err != nil {
+				return fmt.Errorf("unable to unmarshal tx state %s: %w", k, err)
+			}
+			states = append(states, &state)
+			return nil
+		})
+	})
+	return states, err
+}
+
+func (s *BoltStore) PutCheckpoint(chain string, height uint64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(checkpointBucket).Put([]byte(chain), binary.BigEndian.AppendUint64(nil, height))
+	})
+}
+
+func (s *BoltStore) Checkpoints() (map[string]uint64, error) {
+	heights := make(map[string]uint64)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(checkpointBucket).ForEach(func(k, v []byte) error {
+			if len(v) != 8 {
+				return fmt.Errorf("invalid checkpoint for chain %s", k)
+			}
+			heights[string(k)] = binary.BigEndian.Uint64(v)
+			return nil
+		})
+	})
+	return heights, err
+}
+
+func (s *BoltStore) PutDeadLetter(dl *types.DeadLetter) error {
+	bz, err := json.Marshal(dl)
+	if err != nil {
+		return fmt.Errorf("unable to marshal dead letter %s: %w", dl.TxState.TxHash, err)
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(deadLetterBucket).Put([]byte(dl.TxState.TxHash), bz)
+	})
+}
+
+func (s *BoltStore) DeleteDeadLetter(txHash string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(deadLetterBucket).Delete([]byte(txHash))
+	})
+}
+
+func (s *BoltStore) DeadLetters() ([]*types.DeadLetter, error) {
+	var letters []*types.DeadLetter
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(deadLetterBucket).ForEach(func(k, v []byte) error {
+			var dl types.DeadLetter
+			if err := json.Unmarshal(v, &dl); err != nil {
+				return fmt.Errorf("unable to unmarshal dead letter %s: %w", k, err)
+			}
+			letters = append(letters, &dl)
+			return nil
+		})
+	})
+	return letters, err
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,189 @@
+package store_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/store"
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+func TestBoltStoreSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s, err := store.NewBoltStore(path)
+	require.NoError(t, err)
+
+	stateMap, err := types.NewPersistentStateMap(s)
+	require.NoError(t, err)
+
+	inFlight := &types.TxState{
+		TxHash: "0x123",
+		Msgs: []*types.MessageState{
+			{
+				SourceTxHash: "0x123",
+				IrisLookupID: "abc",
+				Status:       types.Pending,
+				DestDomain:   4,
+				MsgSentBytes: []byte("i like turtles"),
+			},
+		},
+	}
+	require.NoError(t, stateMap.Store(inFlight.TxHash, inFlight))
+	require.NoError(t, stateMap.Store("0x456", &types.TxState{TxHash: "0x456"}))
+	require.NoError(t, stateMap.Delete("0x456"))
+
+	// mutate in place and store again, the latest status should be persisted
+	inFlight.Msgs[0].Status = types.Attested
+	require.NoError(t, stateMap.Store(inFlight.TxHash, inFlight))
+	require.NoError(t, stateMap.Close())
+
+	s, err = store.NewBoltStore(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	reloaded, err := types.NewPersistentStateMap(s)
+	require.NoError(t, err)
+
+	tx, ok := reloaded.Load("0x123")
+	require.True(t, ok)
+	require.Len(t, tx.Msgs, 1)
+	require.Equal(t, types.Attested, tx.Msgs[0].Status)
+	require.Equal(t, []byte("i like turtles"), tx.Msgs[0].MsgSentBytes)
+	require.False(t, tx.IsTerminal())
+
+	_, ok = reloaded.Load("0x456")
+	require.False(t, ok)
+}
+
+func TestBoltStoreCheckpoints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s, err := store.NewBoltStore(path)
+	require.NoError(t, err)
+
+	checkpoints, err := types.NewPersistentCheckpoints(s)
+	require.NoError(t, err)
+
+	checkpoints.Set("ethereum", 100)
+	checkpoints.Advance("ethereum", 150)
+	checkpoints.Advance("ethereum", 120) // lower heights are ignored
+	checkpoints.Advance("Noble", 42)
+	require.NoError(t, checkpoints.Flush())
+	require.NoError(t, s.Close())
+
+	s, err = store.NewBoltStore(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	reloaded, err := types.NewPersistentCheckpoints(s)
+	require.NoError(t, err)
+
+	height, ok := reloaded.Load("ethereum")
+	require.True(t, ok)
+	require.Equal(t, uint64(150), height)
+
+	height, ok = reloaded.Load("Noble")
+	require.True(t, ok)
+	require.Equal(t, uint64(42), height)
+
+	reloaded.Clear()
+	_, ok = reloaded.Load("ethereum")
+	require.False(t, ok)
+}
+
+func TestArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.jsonl")
+
+	archive, err := store.NewArchive(path)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	found, err := archive.Find("0x123")
+	require.NoError(t, err)
+	require.Nil(t, found)
+
+	tx := &types.TxState{
+		TxHash: "0x123",
+		Msgs:   []*types.MessageState{{SourceTxHash: "0x123", Status: types.Failed}},
+	}
+	require.NoError(t, archive.Append(tx))
+	tx.Msgs[0].Status = types.Complete
+	require.NoError(t, archive.Append(tx))
+	require.NoError(t, archive.Append(&types.TxState{TxHash: "0x456"}))
+
+	// the most recently archived entry is returned
+	found, err = archive.Find("0x123")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	require.Equal(t, types.Complete, found.TxState.Msgs[0].Status)
+	require.False(t, found.ArchivedAt.IsZero())
+
+	// the index is rebuilt from the file when it is reopened
+	require.NoError(t, archive.Close())
+	archive, err = store.NewArchive(path)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	found, err = archive.Find("0x123")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	require.Equal(t, types.Complete, found.TxState.Msgs[0].Status)
+
+	found, err = archive.Find("0x456")
+	require.NoError(t, err)
+	require.NotNil(t, found)
+
+	found, err = archive.Find("0x789")
+	require.NoError(t, err)
+	require.Nil(t, found)
+}
+
+func TestBoltStoreDeadLetters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+
+	s, err := store.NewBoltStore(path)
+	require.NoError(t, err)
+
+	dlq, err := types.NewPersistentDeadLetterQueue(s)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, dlq.Add(&types.DeadLetter{
+		TxState:   &types.TxState{TxHash: "0x123", Msgs: []*types.MessageState{{SourceTxHash: "0x123", Status: types.Failed}}},
+		Reason:    types.DeadLetterReasonRetryLimit,
+		Attempts:  10,
+		LastError: "attestation not found",
+		Created:   now,
+	}))
+	require.NoError(t, dlq.Add(&types.DeadLetter{
+		TxState: &types.TxState{TxHash: "0x456"},
+		Reason:  types.DeadLetterReasonBroadcast,
+		Created: now.Add(time.Second),
+	}))
+	require.NoError(t, dlq.Add(&types.DeadLetter{
+		TxState: &types.TxState{TxHash: "0x789"},
+		Created: now.Add(2 * time.Second),
+	}))
+	require.NoError(t, dlq.Remove("0x789"))
+	require.NoError(t, s.Close())
+
+	s, err = store.NewBoltStore(path)
+	require.NoError(t, err)
+	defer s.Close()
+
+	reloaded, err := types.NewPersistentDeadLetterQueue(s)
+	require.NoError(t, err)
+
+	letters := reloaded.List()
+	require.Len(t, letters, 2)
+	require.Equal(t, "0x123", letters[0].TxState.TxHash)
+	require.Equal(t, types.DeadLetterReasonRetryLimit, letters[0].Reason)
+	require.Equal(t, 10, letters[0].Attempts)
+	require.Equal(t, "attestation not found", letters[0].LastError)
+	require.Equal(t, types.Failed, letters[0].TxState.Msgs[0].Status)
+	require.Equal(t, "0x456", letters[1].TxState.TxHash)
+}
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"context"
+	"time"
+)
+
 type BlockResponse struct {
 	Result struct {
 		Block struct {
@@ -33,3 +38,59 @@ type Attribute struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
 }
+
+// LastBlockBefore returns the last block with a time before t, or 0 if no block up to latest is.
+// It gallops back from the latest block before searching, so that only recent (unpruned) blocks are queried.
+func LastBlockBefore(
+	ctx context.Context,
+	blockTime func(ctx context.Context, height uint64) (time.Time, error),
+	latest uint64,
+	t time.Time,
+) (uint64, error) {
+	if latest == 0 {
+		return 0, nil
+	}
+	latestTime, err := blockTime(ctx, latest)
+	if err != nil {
+		return 0, err
+	}
+	if latestTime.Before(t) {
+		return latest, nil
+	}
+
+	// find lo and hi such that time(lo) < t <= time(hi)
+	hi := latest
+	var lo uint64
+	for step := uint64(1); ; step *= 2 {
+		candidate := uint64(1)
+		if hi > step+1 {
+			candidate = hi - step
+		}
+		candidateTime, err := blockTime(ctx, candidate)
+		if err != nil {
+			return 0, err
+		}
+		if candidateTime.Before(t) {
+			lo = candidate
+			break
+		}
+		if candidate == 1 {
+			return 0, nil
+		}
+		hi = candidate
+	}
+
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		midTime, err := blockTime(ctx, mid)
+		if err != nil {
+			return 0, err
+		}
+		if midTime.Before(t) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo, nil
+}
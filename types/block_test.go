@@ -0,0 +1,44 @@
+package types_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+func TestLastBlockBefore(t *testing.T) {
+	genesis := time.Unix(1_700_000_000, 0)
+	// block h is produced at genesis + 2h seconds
+	blockTime := func(_ context.Context, height uint64) (time.Time, error) {
+		return genesis.Add(time.Duration(height) * 2 * time.Second), nil
+	}
+	at := func(height uint64) time.Time {
+		return genesis.Add(time.Duration(height) * 2 * time.Second)
+	}
+
+	tests := []struct {
+		name     string
+		latest   uint64
+		t        time.Time
+		expected uint64
+	}{
+		{"latest block is before", 1000, at(1000).Add(time.Second), 1000},
+		{"exact block time", 1000, at(500), 499},
+		{"between blocks", 1000, at(500).Add(time.Second), 500},
+		{"one block back", 1000, at(1000), 999},
+		{"first block", 1000, at(1).Add(time.Second), 1},
+		{"no block before", 1000, at(1), 0},
+		{"no blocks", 0, at(10), 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			height, err := types.LastBlockBefore(context.Background(), blockTime, tc.latest, tc.t)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, height)
+		})
+	}
+}
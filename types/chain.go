@@ -2,6 +2,7 @@ package types
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"cosmossdk.io/log"
@@ -9,6 +10,14 @@ import (
 	"github.com/strangelove-ventures/noble-cctp-relayer/relayer"
 )
 
+// ErrFeeCapExceeded is returned by Broadcast when the network fee of the chain is above its configured cap.
+// The messages are left attested, to be broadcast once the fee drops.
+var ErrFeeCapExceeded = errors.New("network fee above the configured cap")
+
+// ErrTxPending is returned by Broadcast when the only errors are mint txs that were sent but not confirmed in time.
+// They may still be mined, so the messages are waited on again without counting a retry.
+var ErrTxPending = errors.New("mint tx not confirmed yet")
+
 // Chain is an interface for common CCTP source and destination chain operations.
 type Chain interface {
 	// Name returns the name of the chain.
@@ -49,14 +58,56 @@ type Chain interface {
 	) error
 
 	// StartListener starts a listener for observing new CCTP burn messages.
+	// When no start block is configured, the listener resumes from the chain's checkpoint (if any)
+	// and keeps advancing it as blocks are fully processed.
 	StartListener(
 		ctx context.Context,
 		logger log.Logger,
 		processingQueue chan *TxState,
+		checkpoints *Checkpoints,
 		flushOnlyMode bool,
 		flushInterval time.Duration,
 	)
 
+	// ConsumeHistory scans the blocks from start to end for CCTP messages and places their txs on the processing queue.
+	// It returns once every block in the range was scanned.
+	ConsumeHistory(
+		ctx context.Context,
+		logger log.Logger,
+		processingQueue chan *TxState,
+		start, end uint64,
+	) error
+
+	// NonceUsed returns true if the message with the source domain and nonce was already received on the chain.
+	NonceUsed(ctx context.Context, sourceDomain Domain, nonce uint64) (bool, error)
+
+	// QueryLatestBlock queries the latest block height of the chain.
+	QueryLatestBlock(ctx context.Context) (uint64, error)
+
+	// BlockTime queries the time of the block at the height.
+	BlockTime(ctx context.Context, height uint64) (time.Time, error)
+
+	// CheckHealth returns an error describing why the chain is not ready to relay, or nil if it is.
+	CheckHealth(ctx context.Context) error
+
+	// CheckConfig checks the chain's config against its live endpoints, using its own short lived clients.
+	// It does not require InitializeClients.
+	CheckConfig(ctx context.Context) []ConfigCheck
+
+	// TriggerFlush runs the flush mechanism immediately instead of waiting for the next flush interval.
+	// It does not block; a trigger is dropped if one is already pending.
+	TriggerFlush()
+
+	// QueryTxMessages fetches a transaction from the chain and returns the CCTP messages it emitted.
+	QueryTxMessages(
+		ctx context.Context,
+		logger log.Logger,
+		txHash string,
+	) (*TxState, error)
+
+	// CheckFees returns an error wrapping ErrFeeCapExceeded if the network fee of the chain is above its configured cap.
+	CheckFees(ctx context.Context) error
+
 	// Broadcast broadcasts CCTP mint messages to the chain.
 	Broadcast(
 		ctx context.Context,
@@ -66,6 +117,14 @@ type Chain interface {
 		metrics *relayer.PromMetrics,
 	) error
 
+	// Simulate builds and signs CCTP mint messages like Broadcast, but simulates them on the chain instead of
+	// broadcasting them. Messages that would be received are marked simulated, messages that would fail are marked failed.
+	Simulate(
+		ctx context.Context,
+		logger log.Logger,
+		msgs []*MessageState,
+	) error
+
 	TrackLatestBlockHeight(
 		ctx context.Context,
 		logger log.Logger,
@@ -0,0 +1,163 @@
+package types
+
+import (
+	"sync"
+)
+
+// CheckpointStore is a persistence backend for block checkpoints.
+type CheckpointStore interface {
+	// PutCheckpoint persists the last fully processed height for the chain.
+	PutCheckpoint(chain string, height uint64) error
+
+	// Checkpoints returns every persisted chain name -> height.
+	Checkpoints() (map[string]uint64, error)
+}
+
+// Checkpoints tracks the last fully processed block height of each chain's listener.
+// A height is fully processed once every CCTP message at or below it has been persisted by the processor.
+// Listeners resume from these heights after a restart instead of the chain tip.
+//
+// Listeners Hold each tx before passing it to the processing queue, and the processor Releases it once it is
+// persisted. The checkpoint of a chain stays below its lowest held tx.
+type Checkpoints struct {
+	mu      sync.Mutex
+	store   CheckpointStore
+	heights map[string]uint64
+	dirty   map[string]bool
+
+	// scanned is the height each chain's listener has passed to the processing queue
+	scanned map[string]uint64
+	// held maps chain -> source tx hash -> height of the txs that are not persisted yet
+	held map[string]map[string]uint64
+}
+
+func NewCheckpoints() *Checkpoints {
+	return &Checkpoints{
+		heights: map[string]uint64{},
+		dirty:   map[string]bool{},
+		scanned: map[string]uint64{},
+		held:    map[string]map[string]uint64{},
+	}
+}
+
+// NewPersistentCheckpoints returns Checkpoints backed by the given CheckpointStore.
+// All heights already present in the store are loaded into memory.
+func NewPersistentCheckpoints(store CheckpointStore) (*Checkpoints, error) {
+	c := NewCheckpoints()
+	c.store = store
+
+	heights, err := store.Checkpoints()
+	if err != nil {
+		return nil, err
+	}
+	for chain, height := range heights {
+		c.heights[chain] = height
+	}
+	return c, nil
+}
+
+// Load returns the last fully processed height for the chain, if one is known.
+func (c *Checkpoints) Load(chain string) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	height, ok := c.heights[chain]
+	return height, ok
+}
+
+// Set sets the height for the chain, even if it is lower than the current checkpoint.
+// Listeners call Set with the height they start from.
+func (c *Checkpoints) Set(chain string, height uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.heights[chain] = height
+	c.scanned[chain] = height
+	c.dirty[chain] = true
+}
+
+// Advance moves the checkpoint for the chain forward, up to its lowest held tx. Heights lower than the current
+// checkpoint are ignored.
+func (c *Checkpoints) Advance(chain string, height uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if scanned, ok := c.scanned[chain]; ok && height <= scanned {
+		return
+	}
+	c.scanned[chain] = height
+	c.update(chain)
+}
+
+// Hold keeps the checkpoint for the chain below the height until the tx is released.
+// Listeners call Hold before passing a tx to the processing queue.
+func (c *Checkpoints) Hold(chain string, txHash string, height uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	held, ok := c.held[chain]
+	if !ok {
+		held = map[string]uint64{}
+		c.held[chain] = held
+	}
+	if current, ok := held[txHash]; !ok || height < current {
+		held[txHash] = height
+	}
+}
+
+// Release lets the checkpoint move past the tx. The processor calls Release once the tx is persisted.
+func (c *Checkpoints) Release(txHash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for chain, held := range c.held {
+		if _, ok := held[txHash]; ok {
+			delete(held, txHash)
+			c.update(chain)
+		}
+	}
+}
+
+// update sets the checkpoint for the chain to the scanned height, or just below its lowest held tx.
+// The checkpoint never moves back. Callers hold the lock.
+func (c *Checkpoints) update(chain string) {
+	height, ok := c.scanned[chain]
+	if !ok {
+		return
+	}
+	for _, held := range c.held[chain] {
+		if held == 0 {
+			return
+		}
+		if held-1 < height {
+			height = held - 1
+		}
+	}
+	if current, ok := c.heights[chain]; ok && height <= current {
+		return
+	}
+	c.heights[chain] = height
+	c.dirty[chain] = true
+}
+
+// Clear forgets all checkpoints held in memory so listeners fall back to their configured start block.
+func (c *Checkpoints) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.heights = map[string]uint64{}
+	c.dirty = map[string]bool{}
+	c.scanned = map[string]uint64{}
+	c.held = map[string]map[string]uint64{}
+}
+
+// Flush writes every checkpoint changed since the last flush to the CheckpointStore, if any.
+func (c *Checkpoints) Flush() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.store == nil {
+		return nil
+	}
+	for chain := range c.dirty {
+		if err := c.store.PutCheckpoint(chain, c.heights[chain]); err != nil {
+			return err
+		}
+		delete(c.dirty, chain)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpointsHold(t *testing.T) {
+	c := NewCheckpoints()
+	c.Set("ethereum", 100)
+
+	// txs passed to the processing queue hold the checkpoint below them until they are persisted
+	c.Hold("ethereum", "0xa", 105)
+	c.Hold("ethereum", "0xb", 110)
+	c.Advance("ethereum", 120)
+	height, _ := c.Load("ethereum")
+	require.Equal(t, uint64(104), height)
+
+	c.Release("0xa")
+	height, _ = c.Load("ethereum")
+	require.Equal(t, uint64(109), height)
+
+	c.Release("0xb")
+	height, _ = c.Load("ethereum")
+	require.Equal(t, uint64(120), height)
+
+	// a tx queued again below the checkpoint does not move it back
+	c.Hold("ethereum", "0xa", 105)
+	c.Advance("ethereum", 130)
+	height, _ = c.Load("ethereum")
+	require.Equal(t, uint64(120), height)
+
+	c.Release("0xa")
+	height, _ = c.Load("ethereum")
+	require.Equal(t, uint64(130), height)
+
+	// holds are per chain, and releasing an unknown tx is a no-op
+	c.Hold("noble", "0xc", 50)
+	c.Release("0xd")
+	c.Advance("ethereum", 140)
+	height, _ = c.Load("ethereum")
+	require.Equal(t, uint64(140), height)
+}
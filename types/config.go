@@ -1,25 +1,73 @@
 package types
 
+import (
+	"net"
+	"strconv"
+	"time"
+)
+
 type Config struct {
 	Chains        map[string]ChainConfig `yaml:"chains"`
 	EnabledRoutes map[Domain][]Domain    `yaml:"enabled-routes"`
 	Circle        CircleSettings         `yaml:"circle"`
+	State         StateSettings          `yaml:"state"`
 
-	ProcessorWorkerCount uint32 `yaml:"processor-worker-count"`
-	API                  struct {
-		TrustedProxies []string `yaml:"trusted-proxies"`
-	} `yaml:"api"`
+	ProcessorWorkerCount uint32            `yaml:"processor-worker-count"`
+	API                  APISettings       `yaml:"api"`
+	Reconcile            ReconcileSettings `yaml:"reconcile"`
 }
 
 type ConfigWrapper struct {
 	Chains        map[string]map[string]any `yaml:"chains"`
 	EnabledRoutes map[Domain][]Domain       `yaml:"enabled-routes"`
 	Circle        CircleSettings            `yaml:"circle"`
+	State         StateSettings             `yaml:"state"`
+
+	ProcessorWorkerCount uint32            `yaml:"processor-worker-count"`
+	API                  APISettings       `yaml:"api"`
+	Reconcile            ReconcileSettings `yaml:"reconcile"`
+}
+
+const (
+	DefaultAPIAddress = "localhost"
+	DefaultAPIPort    = 8000
+)
+
+type APISettings struct {
+	// Enabled turns the API on or off. The API is enabled when unset
+	Enabled *bool `yaml:"enabled,omitempty"`
+	// Address is the host or ip the API listens on. Defaults to localhost, use 0.0.0.0 to listen on every interface
+	Address string `yaml:"address"`
+	// Port is the port the API listens on. Defaults to 8000
+	Port uint16 `yaml:"port"`
+	// TLSCertFile and TLSKeyFile serve the API over https when both are set
+	TLSCertFile string `yaml:"tls-cert-file"`
+	TLSKeyFile  string `yaml:"tls-key-file"`
+	// CORSOrigins are the origins browsers may call the API from. "*" allows any origin
+	CORSOrigins []string `yaml:"cors-origins"`
+
+	TrustedProxies []string `yaml:"trusted-proxies"`
+	// AdminToken is the bearer token required by the /admin endpoints. The admin API is disabled when empty.
+	// The RELAYER_ADMIN_TOKEN env variable takes precedence.
+	AdminToken string `yaml:"admin-token"`
+}
+
+// IsEnabled returns true unless the API was explicitly disabled.
+func (s APISettings) IsEnabled() bool {
+	return s.Enabled == nil || *s.Enabled
+}
 
-	ProcessorWorkerCount uint32 `yaml:"processor-worker-count"`
-	API                  struct {
-		TrustedProxies []string `yaml:"trusted-proxies"`
-	} `yaml:"api"`
+// ListenAddr returns the host:port the API listens on.
+func (s APISettings) ListenAddr() string {
+	address := s.Address
+	if address == "" {
+		address = DefaultAPIAddress
+	}
+	port := s.Port
+	if port == 0 {
+		port = DefaultAPIPort
+	}
+	return net.JoinHostPort(address, strconv.Itoa(int(port)))
 }
 
 type CircleSettings struct {
@@ -28,6 +76,51 @@ type CircleSettings struct {
 	FetchRetryInterval int    `yaml:"fetch-retry-interval"`
 }
 
+// StateSettings configures where the relayer keeps its message state.
+type StateSettings struct {
+	// Backend is either "memory" (default) or "bolt"
+	Backend string `yaml:"backend"`
+	// Path is the database file used by the bolt backend
+	Path string `yaml:"path"`
+
+	Retention RetentionSettings `yaml:"retention"`
+}
+
+// RetentionSettings bounds how many terminal (complete, failed, filtered) tx states are kept.
+type RetentionSettings struct {
+	// TTL is how long a terminal tx state is kept after it was last updated. 0 keeps them forever
+	TTL time.Duration `yaml:"ttl"`
+	// MaxEntries is the maximum number of tx states kept. 0 is unlimited
+	MaxEntries int `yaml:"max-entries"`
+	// SweepInterval is how often the retention policy is applied
+	SweepInterval time.Duration `yaml:"sweep-interval"`
+	// ArchiveFile is an optional JSONL file that evicted tx states are appended to
+	ArchiveFile string `yaml:"archive-file"`
+}
+
+// ReconcileSettings configures the reconciliation job that looks for burns with no matching mint.
+type ReconcileSettings struct {
+	// Interval is how often the job runs while the relayer is running. 0 disables the job
+	Interval time.Duration `yaml:"interval"`
+	// Window is how far back burns are checked, counted from the threshold. Defaults to 24h
+	Window time.Duration `yaml:"window"`
+	// Threshold is how old a burn must be before a missing mint is reported, leaving the relayer time to mint it.
+	// Defaults to 30m
+	Threshold time.Duration `yaml:"threshold"`
+	// Requeue places unminted messages back on the processing queue of the running relayer
+	Requeue bool `yaml:"requeue"`
+	// Windows overrides the window of the named chains. Each Noble block is queried on its own, so the Noble
+	// window defaults to 1h
+	Windows map[string]time.Duration `yaml:"windows"`
+}
+
 type ChainConfig interface {
 	Chain(name string) (Chain, error)
+
+	// MinterAddress returns the address of the minter private key and where the key was found,
+	// without creating the chain. The key itself is never returned.
+	MinterAddress(name string) (address string, source string, err error)
+
+	// QueryChain creates the chain without the minter private key. The chain can be queried, but not broadcast to.
+	QueryChain(name string) (Chain, error)
 }
@@ -0,0 +1,18 @@
+package types
+
+// ConfigCheck is the outcome of a single live check of the config.
+// On failure, Detail holds the reason.
+type ConfigCheck struct {
+	Chain  string `json:"chain"`
+	Check  string `json:"check"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// NewConfigCheck returns a passed check with the given detail, or a failed one if err is not nil.
+func NewConfigCheck(chain string, check string, detail string, err error) ConfigCheck {
+	if err != nil {
+		return ConfigCheck{Chain: chain, Check: check, Detail: err.Error()}
+	}
+	return ConfigCheck{Chain: chain, Check: check, Passed: true, Detail: detail}
+}
@@ -0,0 +1,116 @@
+package types
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DeadLetterReasonRetryLimit = "retry limit exceeded"
+	DeadLetterReasonBroadcast  = "broadcast failed"
+)
+
+// DeadLetter is a TxState the processor gave up on, along with why.
+type DeadLetter struct {
+	TxState   *TxState  `json:"tx_state"`
+	Reason    string    `json:"reason"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	Created   time.Time `json:"created"`
+}
+
+// DeadLetterStore is a persistence backend for dead letters.
+type DeadLetterStore interface {
+	// PutDeadLetter persists the dead letter under its source tx hash.
+	PutDeadLetter(dl *DeadLetter) error
+
+	// DeleteDeadLetter removes the dead letter for the source tx hash.
+	DeleteDeadLetter(txHash string) error
+
+	// DeadLetters returns every persisted dead letter.
+	DeadLetters() ([]*DeadLetter, error)
+}
+
+// DeadLetterQueue holds TxStates that exhausted their retries so they can be inspected and replayed by an operator.
+type DeadLetterQueue struct {
+	mu      sync.Mutex
+	store   DeadLetterStore
+	letters map[string]*DeadLetter
+}
+
+func NewDeadLetterQueue() *DeadLetterQueue {
+	return &DeadLetterQueue{
+		letters: map[string]*DeadLetter{},
+	}
+}
+
+// NewPersistentDeadLetterQueue returns a DeadLetterQueue backed by the given DeadLetterStore.
+// All dead letters already present in the store are loaded into memory.
+func NewPersistentDeadLetterQueue(store DeadLetterStore) (*DeadLetterQueue, error) {
+	q := NewDeadLetterQueue()
+	q.store = store
+
+	letters, err := store.DeadLetters()
+	if err != nil {
+		return nil, err
+	}
+	for _, dl := range letters {
+		q.letters[dl.TxState.TxHash] = dl
+	}
+	return q, nil
+}
+
+// Add places the dead letter in the queue, replacing any existing entry for the same source tx hash.
+func (q *DeadLetterQueue) Add(dl *DeadLetter) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.store != nil {
+		if err := q.store.PutDeadLetter(dl); err != nil {
+			return err
+		}
+	}
+	q.letters[dl.TxState.TxHash] = dl
+	return nil
+}
+
+// Load returns the dead letter for the source tx hash, if there is one.
+func (q *DeadLetterQueue) Load(txHash string) (*DeadLetter, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	dl, ok := q.letters[txHash]
+	return dl, ok
+}
+
+// Remove deletes the dead letter for the source tx hash.
+func (q *DeadLetterQueue) Remove(txHash string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.store != nil {
+		if err := q.store.DeleteDeadLetter(txHash); err != nil {
+			return err
+		}
+	}
+	delete(q.letters, txHash)
+	return nil
+}
+
+// List returns every dead letter, oldest first.
+func (q *DeadLetterQueue) List() []*DeadLetter {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	letters := make([]*DeadLetter, 0, len(q.letters))
+	for _, dl := range q.letters {
+		letters = append(letters, dl)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		if letters[i].Created.Equal(letters[j].Created) {
+			return letters[i].TxState.TxHash < letters[j].TxState.TxHash
+		}
+		return letters[i].Created.Before(letters[j].Created)
+	})
+	return letters
+}
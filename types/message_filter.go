@@ -0,0 +1,102 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// MessageFilter selects MessageStates from the StateMap. Zero value fields match every message.
+type MessageFilter struct {
+	Statuses      []string
+	SourceDomain  *Domain
+	DestDomain    *Domain
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+}
+
+// Matches returns true if the message satisfies every condition of the filter.
+func (f MessageFilter) Matches(msg *MessageState) bool {
+	if len(f.Statuses) > 0 {
+		var found bool
+		for _, status := range f.Statuses {
+			if msg.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.SourceDomain != nil && msg.SourceDomain != *f.SourceDomain {
+		return false
+	}
+	if f.DestDomain != nil && msg.DestDomain != *f.DestDomain {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && msg.Created.Before(f.CreatedAfter) {
+		return false
+	}
+	if !f.CreatedBefore.IsZero() && !msg.Created.Before(f.CreatedBefore) {
+		return false
+	}
+	if !f.UpdatedAfter.IsZero() && msg.Updated.Before(f.UpdatedAfter) {
+		return false
+	}
+	if !f.UpdatedBefore.IsZero() && !msg.Updated.Before(f.UpdatedBefore) {
+		return false
+	}
+	return true
+}
+
+// Messages returns a copy of every MessageState matching the filter, oldest first.
+// Ties are broken by source tx hash and then nonce so that pages are stable between requests.
+func (sm *StateMap) Messages(filter MessageFilter) []MessageState {
+	sm.Mu.Lock()
+	defer sm.Mu.Unlock()
+
+	var msgs []MessageState
+	sm.Range(func(_ string, tx *TxState) bool {
+		for _, msg := range tx.Msgs {
+			if filter.Matches(msg) {
+				msgs = append(msgs, *msg)
+			}
+		}
+		return true
+	})
+
+	sort.Slice(msgs, func(i, j int) bool {
+		switch {
+		case !msgs[i].Created.Equal(msgs[j].Created):
+			return msgs[i].Created.Before(msgs[j].Created)
+		case msgs[i].SourceTxHash != msgs[j].SourceTxHash:
+			return msgs[i].SourceTxHash < msgs[j].SourceTxHash
+		default:
+			return msgs[i].Nonce < msgs[j].Nonce
+		}
+	})
+	return msgs
+}
+
+// FindMessage returns a copy of the first MessageState for which match returns true.
+func (sm *StateMap) FindMessage(match func(msg *MessageState) bool) (MessageState, bool) {
+	sm.Mu.Lock()
+	defer sm.Mu.Unlock()
+
+	var found *MessageState
+	sm.Range(func(_ string, tx *TxState) bool {
+		for _, msg := range tx.Msgs {
+			if match(msg) {
+				found = msg
+				return false
+			}
+		}
+		return true
+	})
+	if found == nil {
+		return MessageState{}, false
+	}
+	return *found, true
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/circlefin/noble-cctp/x/cctp/types"
@@ -19,6 +20,8 @@ const (
 	Complete string = "complete"
 	Failed   string = "failed"
 	Filtered string = "filtered"
+	// Simulated messages were simulated on the destination chain by a dry run instead of being broadcast
+	Simulated string = "simulated"
 
 	Mint    string = "mint"
 	Forward string = "forward"
@@ -32,9 +35,32 @@ type TxState struct {
 	RetryAttempt int
 }
 
+// LastUpdated returns the most recent update time of any message in the TxState.
+func (t *TxState) LastUpdated() time.Time {
+	var last time.Time
+	for _, msg := range t.Msgs {
+		if msg.Updated.After(last) {
+			last = msg.Updated
+		}
+	}
+	return last
+}
+
+// IsTerminal returns true if every message in the TxState has reached a status that will no longer change.
+func (t *TxState) IsTerminal() bool {
+	for _, msg := range t.Msgs {
+		switch msg.Status {
+		case Complete, Failed, Filtered, Simulated:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 type MessageState struct {
 	IrisLookupID      string // hex encoded MessageSent bytes
-	Status            string // created, pending, attested, complete, failed, filtered
+	Status            string // created, pending, attested, complete, failed, filtered, simulated
 	Attestation       string // hex encoded attestation
 	SourceDomain      Domain // uint32 source domain id
 	DestDomain        Domain // uint32 destination domain id
@@ -47,6 +73,8 @@ type MessageState struct {
 	Created           time.Time
 	Updated           time.Time
 	Nonce             uint64
+	GasUsed           uint64   // gas used by the mint tx, set once its receipt is confirmed (EVM only)
+	EffectiveGasPrice *big.Int // price paid per gas by the mint tx in wei, set once its receipt is confirmed (EVM only)
 }
 
 // EvmLogToMessageState transforms an evm log into a messageState given an ABI
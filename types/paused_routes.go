@@ -0,0 +1,61 @@
+package types
+
+import (
+	"sort"
+	"sync"
+)
+
+// Route is a source domain -> destination domain pair.
+type Route struct {
+	SourceDomain Domain `json:"source_domain"`
+	DestDomain   Domain `json:"dest_domain"`
+}
+
+// PausedRoutes tracks enabled routes that an operator paused at runtime.
+// Messages on a paused route are held until the route is resumed.
+type PausedRoutes struct {
+	mu     sync.Mutex
+	routes map[Route]bool
+}
+
+func NewPausedRoutes() *PausedRoutes {
+	return &PausedRoutes{
+		routes: map[Route]bool{},
+	}
+}
+
+func (p *PausedRoutes) Pause(route Route) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.routes[route] = true
+}
+
+func (p *PausedRoutes) Resume(route Route) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.routes, route)
+}
+
+func (p *PausedRoutes) IsPaused(route Route) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.routes[route]
+}
+
+// List returns the paused routes ordered by source and then destination domain.
+func (p *PausedRoutes) List() []Route {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	routes := make([]Route, 0, len(p.routes))
+	for route := range p.routes {
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].SourceDomain != routes[j].SourceDomain {
+			return routes[i].SourceDomain < routes[j].SourceDomain
+		}
+		return routes[i].DestDomain < routes[j].DestDomain
+	})
+	return routes
+}
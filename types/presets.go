@@ -0,0 +1,143 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+)
+
+// NetworkPreset holds the known values of a CCTP network
+type NetworkPreset struct {
+	AttestationBaseURL string
+	Chains             map[string]ChainPreset
+}
+
+// ChainPreset holds the known values of a chain on a CCTP network.
+// EVMChainID is set for EVM chains and CosmosChainID for Noble.
+type ChainPreset struct {
+	Domain             Domain
+	EVMChainID         int64
+	CosmosChainID      string
+	MessageTransmitter string
+	// LookbackPeriod covers roughly 5 minutes of blocks
+	LookbackPeriod  uint64
+	MetricsDenom    string
+	MetricsExponent int
+}
+
+// NetworkPresets is the built-in preset registry, keyed by network.
+// MessageTransmitter addresses: https://developers.circle.com/stablecoins/docs/evm-smart-contracts
+var NetworkPresets = map[string]NetworkPreset{
+	NetworkMainnet: {
+		AttestationBaseURL: "https://iris-api.circle.com/attestations/",
+		Chains: map[string]ChainPreset{
+			"noble": {Domain: 4, CosmosChainID: "noble-1", LookbackPeriod: 150},
+			"ethereum": {
+				Domain: 0, EVMChainID: 1, MessageTransmitter: "0x0a992d191DEeC32aFe36203Ad87D7d289a738F81",
+				LookbackPeriod: 25, MetricsDenom: "ETH", MetricsExponent: 18,
+			},
+			"avalanche": {
+				Domain: 1, EVMChainID: 43114, MessageTransmitter: "0x8186359aF5F57FbB40c6b14A588d2A59C0C29880",
+				LookbackPeriod: 150, MetricsDenom: "AVAX", MetricsExponent: 18,
+			},
+			"optimism": {
+				Domain: 2, EVMChainID: 10, MessageTransmitter: "0x4D41f22c5a0e5c74090899E5a8Fb597a8842b3e8",
+				LookbackPeriod: 150, MetricsDenom: "ETH", MetricsExponent: 18,
+			},
+			"arbitrum": {
+				Domain: 3, EVMChainID: 42161, MessageTransmitter: "0xC30362313FBBA5cf9163F0bb16a0e01f01A896ca",
+				LookbackPeriod: 1200, MetricsDenom: "ETH", MetricsExponent: 18,
+			},
+			"base": {
+				Domain: 6, EVMChainID: 8453, MessageTransmitter: "0xAD09780d193884d503182aD4588450C416D6F9D4",
+				LookbackPeriod: 150, MetricsDenom: "ETH", MetricsExponent: 18,
+			},
+			"polygon": {
+				Domain: 7, EVMChainID: 137, MessageTransmitter: "0xF3be9355363857F3e001be68856A2f96b4C39Ba9",
+				LookbackPeriod: 150, MetricsDenom: "POL", MetricsExponent: 18,
+			},
+		},
+	},
+	NetworkTestnet: {
+		AttestationBaseURL: "https://iris-api-sandbox.circle.com/attestations/",
+		Chains: map[string]ChainPreset{
+			"noble": {Domain: 4, CosmosChainID: "grand-1", LookbackPeriod: 150},
+			"ethereum": {
+				Domain: 0, EVMChainID: 11155111, MessageTransmitter: "0x7865fAfC2db2093669d92c0F33AeEF291086BEFD",
+				LookbackPeriod: 25, MetricsDenom: "ETH", MetricsExponent: 18,
+			},
+			"avalanche": {
+				Domain: 1, EVMChainID: 43113, MessageTransmitter: "0xa9fB1b3009DCb79E2fe346c16a604B8Fa8aE0a79",
+				LookbackPeriod: 150, MetricsDenom: "AVAX", MetricsExponent: 18,
+			},
+			"optimism": {
+				Domain: 2, EVMChainID: 11155420, MessageTransmitter: "0x7865fAfC2db2093669d92c0F33AeEF291086BEFD",
+				LookbackPeriod: 150, MetricsDenom: "ETH", MetricsExponent: 18,
+			},
+			"arbitrum": {
+				Domain: 3, EVMChainID: 421614, MessageTransmitter: "0xaCF1ceeF35caAc005e15888dDb8A3515C41B4872",
+				LookbackPeriod: 1200, MetricsDenom: "ETH", MetricsExponent: 18,
+			},
+			"base": {
+				Domain: 6, EVMChainID: 84532, MessageTransmitter: "0x7865fAfC2db2093669d92c0F33AeEF291086BEFD",
+				LookbackPeriod: 150, MetricsDenom: "ETH", MetricsExponent: 18,
+			},
+			"polygon": {
+				Domain: 7, EVMChainID: 80002, MessageTransmitter: "0x7865fAfC2db2093669d92c0F33AeEF291086BEFD",
+				LookbackPeriod: 150, MetricsDenom: "POL", MetricsExponent: 18,
+			},
+		},
+	},
+}
+
+// LookupChainPreset returns the chain preset referenced as "<network>/<chain>", ex: "mainnet/ethereum".
+func LookupChainPreset(name string) (ChainPreset, error) {
+	network, chain, ok := strings.Cut(name, "/")
+	if !ok {
+		return ChainPreset{}, fmt.Errorf("invalid preset %q, expected <network>/<chain>", name)
+	}
+	networkPreset, ok := NetworkPresets[network]
+	if !ok {
+		return ChainPreset{}, fmt.Errorf("unknown network %q in preset %q, expected one of %s", network, name, strings.Join(PresetNetworks(), ", "))
+	}
+	preset, ok := networkPreset.Chains[chain]
+	if !ok {
+		return ChainPreset{}, fmt.Errorf("unknown chain %q in preset %q, expected one of %s", chain, name, strings.Join(networkPreset.ChainNames(), ", "))
+	}
+	return preset, nil
+}
+
+// PresetNetworks returns the names of the preset networks in alphabetical order.
+func PresetNetworks() []string {
+	networks := make([]string, 0, len(NetworkPresets))
+	for network := range NetworkPresets {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+	return networks
+}
+
+// ChainNames returns the names of the network's preset chains in alphabetical order.
+func (n NetworkPreset) ChainNames() []string {
+	names := make([]string, 0, len(n.Chains))
+	for name := range n.Chains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DomainName returns the name of the chain with the domain, or "unknown" if it has no preset.
+func DomainName(domain Domain) string {
+	for name, preset := range NetworkPresets[NetworkMainnet].Chains {
+		if preset.Domain == domain {
+			return name
+		}
+	}
+	return "unknown"
+}
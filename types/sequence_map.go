@@ -1,6 +1,7 @@
 package types
 
 import (
+	"slices"
 	"sync"
 )
 
@@ -9,24 +10,81 @@ type SequenceMap struct {
 	mu sync.Mutex
 	// map destination domain -> minter account sequence
 	sequenceMap map[Domain]uint64
+	// map destination domain -> sorted sequences below the next one that were released unused
+	released map[Domain][]uint64
 }
 
 func NewSequenceMap() *SequenceMap {
 	return &SequenceMap{
 		sequenceMap: map[Domain]uint64{},
+		released:    map[Domain][]uint64{},
 	}
 }
 
+// Put resets the next sequence of the domain, ex. to the account's pending nonce. Released sequences are dropped.
 func (m *SequenceMap) Put(destDomain Domain, val uint64) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.sequenceMap[destDomain] = val
+	delete(m.released, destDomain)
 }
 
+// Next allocates a sequence of the domain. Released sequences are handed out first, lowest first, so no gaps are left.
 func (m *SequenceMap) Next(destDomain Domain) uint64 {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if released := m.released[destDomain]; len(released) > 0 {
+		m.released[destDomain] = released[1:]
+		return released[0]
+	}
 	result := m.sequenceMap[destDomain]
 	m.sequenceMap[destDomain]++
 	return result
 }
+
+// Release returns a sequence from Next that no tx was submitted with, so that it is allocated again.
+// Sequences at or above the next one, ex. after a Put, are ignored.
+func (m *SequenceMap) Release(destDomain Domain, val uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if val >= m.sequenceMap[destDomain] {
+		return
+	}
+
+	released := m.released[destDomain]
+	i, found := slices.BinarySearch(released, val)
+	if found {
+		return
+	}
+	released = slices.Insert(released, i, val)
+
+	// released sequences directly below the next one are simply allocated again by it
+	next := m.sequenceMap[destDomain]
+	for len(released) > 0 && released[len(released)-1] == next-1 {
+		released = released[:len(released)-1]
+		next--
+	}
+	m.sequenceMap[destDomain] = next
+	m.released[destDomain] = released
+}
+
+// Released returns the sequences of the domain that were released below the next one, lowest first.
+// Each one is a gap that blocks the txs signed with higher sequences until it is allocated again.
+func (m *SequenceMap) Released(destDomain Domain) []uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.released[destDomain])
+}
+
+// Claim allocates a released sequence of the domain, and reports whether it was still released.
+func (m *SequenceMap) Claim(destDomain Domain, val uint64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	released := m.released[destDomain]
+	i, found := slices.BinarySearch(released, val)
+	if !found {
+		return false
+	}
+	m.released[destDomain] = slices.Delete(released, i, i+1)
+	return true
+}
@@ -0,0 +1,54 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+func TestSequenceMap(t *testing.T) {
+	m := types.NewSequenceMap()
+	m.Put(0, 10)
+
+	require.Equal(t, uint64(10), m.Next(0))
+	require.Equal(t, uint64(11), m.Next(0))
+	require.Equal(t, uint64(12), m.Next(0))
+	require.Equal(t, uint64(13), m.Next(0))
+
+	// gaps are filled lowest first
+	m.Release(0, 12)
+	m.Release(0, 11)
+	require.Equal(t, uint64(11), m.Next(0))
+	require.Equal(t, uint64(12), m.Next(0))
+	require.Equal(t, uint64(14), m.Next(0))
+
+	// releasing the latest sequences rewinds the next one
+	m.Release(0, 13)
+	m.Release(0, 14)
+	require.Equal(t, uint64(13), m.Next(0))
+
+	// releases are dropped on a reset, and ignored above the next sequence
+	m.Release(0, 11)
+	m.Put(0, 20)
+	m.Release(0, 25)
+	require.Equal(t, uint64(20), m.Next(0))
+	require.Equal(t, uint64(21), m.Next(0))
+
+	// released sequences can be claimed out of order
+	m.Next(0)
+	m.Next(0)
+	m.Release(0, 20)
+	m.Release(0, 21)
+	require.Equal(t, []uint64{20, 21}, m.Released(0))
+	require.True(t, m.Claim(0, 21))
+	require.False(t, m.Claim(0, 21))
+	require.False(t, m.Claim(0, 22))
+	require.Equal(t, []uint64{20}, m.Released(0))
+	require.Equal(t, uint64(20), m.Next(0))
+	require.Empty(t, m.Released(0))
+
+	// domains are independent
+	require.Equal(t, uint64(0), m.Next(4))
+}
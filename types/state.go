@@ -1,7 +1,9 @@
 package types
 
 import (
+	"sort"
 	"sync"
+	"time"
 )
 
 // StateMap wraps sync.Map with type safety
@@ -9,6 +11,9 @@ import (
 type StateMap struct {
 	Mu       sync.Mutex
 	internal sync.Map
+
+	// store is an optional persistence backend. When set, every Store and Delete is written through to it.
+	store StateStore
 }
 
 func NewStateMap() *StateMap {
@@ -18,6 +23,22 @@ func NewStateMap() *StateMap {
 	}
 }
 
+// NewPersistentStateMap returns a StateMap backed by the given StateStore.
+// All TxStates already present in the store are loaded into memory.
+func NewPersistentStateMap(store StateStore) (*StateMap, error) {
+	sm := NewStateMap()
+	sm.store = store
+
+	states, err := store.All()
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		sm.internal.Store(state.TxHash, state)
+	}
+	return sm, nil
+}
+
 // load loads the message states tied to a specific transaction hash
 func (sm *StateMap) Load(key string) (value *TxState, ok bool) {
 	sm.Mu.Lock()
@@ -30,17 +51,74 @@ func (sm *StateMap) Load(key string) (value *TxState, ok bool) {
 	return internalResult.(*TxState), ok
 }
 
-func (sm *StateMap) Delete(key string) {
+func (sm *StateMap) Delete(key string) error {
 	sm.Mu.Lock()
 	defer sm.Mu.Unlock()
 
 	sm.internal.Delete(key)
+
+	if sm.store != nil {
+		return sm.store.Delete(key)
+	}
+	return nil
 }
 
-// store stores the message states tied to a specific transaction hash
-func (sm *StateMap) Store(key string, value *TxState) {
+// store stores the message states tied to a specific transaction hash.
+// Message states are often mutated in place, call Store again after mutating to persist the changes.
+// The TxState is encoded by the StateStore while Mu is held, so message states must be mutated with Mu held.
+func (sm *StateMap) Store(key string, value *TxState) error {
 	sm.Mu.Lock()
 	defer sm.Mu.Unlock()
 
 	sm.internal.Store(key, value)
+
+	if sm.store != nil {
+		return sm.store.Put(key, value)
+	}
+	return nil
+}
+
+// Range calls f sequentially for each tx hash and TxState in the map. If f returns false, range stops the iteration.
+func (sm *StateMap) Range(f func(key string, value *TxState) bool) {
+	sm.internal.Range(func(key, value any) bool {
+		return f(key.(string), value.(*TxState))
+	})
+}
+
+// Evictable returns the terminal TxStates that should be dropped to satisfy the retention policy, oldest first.
+// A TxState is evictable when it was last updated more than ttl ago, or when the map holds more than
+// maxEntries TxStates. In-flight TxStates are never returned. A zero ttl or maxEntries disables that limit.
+func (sm *StateMap) Evictable(ttl time.Duration, maxEntries int, now time.Time) []*TxState {
+	var total int
+	var terminal []*TxState
+	sm.Range(func(_ string, tx *TxState) bool {
+		total++
+		if tx.IsTerminal() {
+			terminal = append(terminal, tx)
+		}
+		return true
+	})
+
+	sort.Slice(terminal, func(i, j int) bool {
+		return terminal[i].LastUpdated().Before(terminal[j].LastUpdated())
+	})
+
+	var evict int
+	for evict < len(terminal) {
+		expired := ttl > 0 && now.Sub(terminal[evict].LastUpdated()) > ttl
+		overCapacity := maxEntries > 0 && total-evict > maxEntries
+		if !expired && !overCapacity {
+			break
+		}
+		evict++
+	}
+	return terminal[:evict]
+}
+
+// Close closes the underlying StateStore, if any.
+func (sm *StateMap) Close() error {
+	if sm.store != nil {
+		return sm.store.Close()
+	}
+	return nil
 }
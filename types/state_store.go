@@ -0,0 +1,22 @@
+package types
+
+const (
+	StateBackendMemory = "memory"
+	StateBackendBolt   = "bolt"
+)
+
+// StateStore is a persistence backend for the StateMap.
+// It allows in-flight TxStates to survive a restart of the relayer.
+type StateStore interface {
+	// Put persists the TxState under its source tx hash. It is called with the StateMap's Mu held.
+	Put(txHash string, state *TxState) error
+
+	// Delete removes the TxState for the source tx hash.
+	Delete(txHash string) error
+
+	// All returns every persisted TxState.
+	All() ([]*TxState, error)
+
+	// Close releases the underlying resources.
+	Close() error
+}
@@ -1,8 +1,12 @@
 package types
 
 import (
+	"encoding/json"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,12 +21,13 @@ func TestStateHandling(t *testing.T) {
 		MsgSentBytes: []byte("i like turtles"),
 	}
 
-	stateMap.Store(txHash, &TxState{
+	err := stateMap.Store(txHash, &TxState{
 		TxHash: txHash,
 		Msgs: []*MessageState{
 			&msg,
 		},
 	})
+	require.NoError(t, err)
 
 	loadedMsg, _ := stateMap.Load(txHash)
 	require.True(t, msg.Equal(loadedMsg.Msgs[0]))
@@ -43,8 +48,147 @@ func TestStateHandling(t *testing.T) {
 	}
 
 	loadedMsg.Msgs = append(loadedMsg.Msgs, &msg2)
-	stateMap.Store(txHash, loadedMsg)
+	err = stateMap.Store(txHash, loadedMsg)
+	require.NoError(t, err)
 
 	loadedMsg3, _ := stateMap.Load(txHash)
 	require.Len(t, loadedMsg3.Msgs, 2)
 }
+
+func TestStateEvictable(t *testing.T) {
+	stateMap := NewStateMap()
+	now := time.Now()
+
+	store := func(txHash string, status string, updated time.Time) {
+		require.NoError(t, stateMap.Store(txHash, &TxState{
+			TxHash: txHash,
+			Msgs:   []*MessageState{{SourceTxHash: txHash, Status: status, Updated: updated}},
+		}))
+	}
+	store("old-complete", Complete, now.Add(-3*time.Hour))
+	store("old-pending", Pending, now.Add(-4*time.Hour))
+	store("recent-failed", Failed, now.Add(-time.Hour))
+	store("new-filtered", Filtered, now)
+
+	// ttl only, in-flight txs are never evicted
+	evictable := stateMap.Evictable(2*time.Hour, 0, now)
+	require.Len(t, evictable, 1)
+	require.Equal(t, "old-complete", evictable[0].TxHash)
+
+	// max entries only, oldest terminal txs are evicted first
+	evictable = stateMap.Evictable(0, 2, now)
+	require.Len(t, evictable, 2)
+	require.Equal(t, "old-complete", evictable[0].TxHash)
+	require.Equal(t, "recent-failed", evictable[1].TxHash)
+
+	// no limits configured
+	require.Empty(t, stateMap.Evictable(0, 0, now))
+}
+
+func TestStateMessages(t *testing.T) {
+	stateMap := NewStateMap()
+	now := time.Now()
+
+	require.NoError(t, stateMap.Store("0x1", &TxState{
+		TxHash: "0x1",
+		Msgs: []*MessageState{
+			{SourceTxHash: "0x1", Status: Pending, SourceDomain: 0, DestDomain: 4, Nonce: 2, Created: now.Add(-2 * time.Hour), IrisLookupID: "aa"},
+			{SourceTxHash: "0x1", Status: Complete, SourceDomain: 0, DestDomain: 4, Nonce: 1, Created: now.Add(-2 * time.Hour), IrisLookupID: "bb"},
+		},
+	}))
+	require.NoError(t, stateMap.Store("0x2", &TxState{
+		TxHash: "0x2",
+		Msgs: []*MessageState{
+			{SourceTxHash: "0x2", Status: Attested, SourceDomain: 4, DestDomain: 0, Nonce: 7, Created: now.Add(-time.Hour), IrisLookupID: "cc"},
+		},
+	}))
+
+	msgs := stateMap.Messages(MessageFilter{})
+	require.Len(t, msgs, 3)
+	// oldest first, ties broken by tx hash and nonce
+	require.Equal(t, uint64(1), msgs[0].Nonce)
+	require.Equal(t, uint64(2), msgs[1].Nonce)
+	require.Equal(t, uint64(7), msgs[2].Nonce)
+
+	msgs = stateMap.Messages(MessageFilter{Statuses: []string{Pending, Attested}})
+	require.Len(t, msgs, 2)
+
+	noble := Domain(4)
+	msgs = stateMap.Messages(MessageFilter{SourceDomain: &noble})
+	require.Len(t, msgs, 1)
+	require.Equal(t, "cc", msgs[0].IrisLookupID)
+
+	msgs = stateMap.Messages(MessageFilter{DestDomain: &noble, CreatedBefore: now.Add(-90 * time.Minute)})
+	require.Len(t, msgs, 2)
+
+	msgs = stateMap.Messages(MessageFilter{CreatedAfter: now.Add(-90 * time.Minute)})
+	require.Len(t, msgs, 1)
+
+	msg, ok := stateMap.FindMessage(func(msg *MessageState) bool {
+		return msg.SourceDomain == 0 && msg.Nonce == 2
+	})
+	require.True(t, ok)
+	require.Equal(t, "aa", msg.IrisLookupID)
+
+	_, ok = stateMap.FindMessage(func(msg *MessageState) bool { return msg.Nonce == 100 })
+	require.False(t, ok)
+}
+
+// jsonStateStore encodes each TxState it is given, like a persistent StateStore
+type jsonStateStore struct {
+	encoded map[string][]byte
+}
+
+func (s *jsonStateStore) Put(txHash string, state *TxState) error {
+	bz, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	s.encoded[txHash] = bz
+	return nil
+}
+
+func (s *jsonStateStore) Delete(txHash string) error {
+	delete(s.encoded, txHash)
+	return nil
+}
+
+func (s *jsonStateStore) All() ([]*TxState, error) { return nil, nil }
+
+func (s *jsonStateStore) Close() error { return nil }
+
+// TestStateStoreEncodesUnderMu is meant to be run with -race. Messages mutated with Mu held are not encoded concurrently.
+func TestStateStoreEncodesUnderMu(t *testing.T) {
+	store := &jsonStateStore{encoded: make(map[string][]byte)}
+	stateMap, err := NewPersistentStateMap(store)
+	require.NoError(t, err)
+
+	tx := &TxState{TxHash: "0x1", Msgs: []*MessageState{{SourceTxHash: "0x1", Status: Created}}}
+	require.NoError(t, stateMap.Store(tx.TxHash, tx))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			stateMap.Mu.Lock()
+			tx.Msgs[0].Status = Pending
+			tx.Msgs[0].Updated = time.Now()
+			tx.RetryAttempt++
+			stateMap.Mu.Unlock()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, stateMap.Store(tx.TxHash, tx))
+		}
+	}()
+	wg.Wait()
+
+	require.NoError(t, stateMap.Store(tx.TxHash, tx))
+	var stored TxState
+	require.NoError(t, json.Unmarshal(store.encoded[tx.TxHash], &stored))
+	require.Equal(t, 100, stored.RetryAttempt)
+	require.Equal(t, Pending, stored.Msgs[0].Status)
+}
@@ -0,0 +1,106 @@
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+// StatusEvent records a single MessageState status transition.
+type StatusEvent struct {
+	ID             uint64    `json:"id"`
+	TxHash         string    `json:"tx_hash"`
+	IrisLookupID   string    `json:"iris_lookup_id"`
+	SourceDomain   Domain    `json:"source_domain"`
+	DestDomain     Domain    `json:"dest_domain"`
+	Nonce          uint64    `json:"nonce"`
+	PreviousStatus string    `json:"previous_status"`
+	Status         string    `json:"status"`
+	Time           time.Time `json:"time"`
+}
+
+// StatusEvents fans out status transitions to subscribers and keeps the most recent ones in a ring buffer,
+// so that a subscriber that reconnects can catch up on the transitions it missed.
+//
+// Event IDs increase monotonically. They start from the process start time in nanoseconds, so a cursor held
+// from before a relayer restart is always lower than every ID issued after it.
+type StatusEvents struct {
+	mu     sync.Mutex
+	nextID uint64
+	buffer []StatusEvent
+	start  int // index of the oldest event in buffer
+	size   int
+	subs   map[chan StatusEvent]struct{}
+}
+
+// NewStatusEvents returns StatusEvents that remember up to capacity events.
+func NewStatusEvents(capacity int) *StatusEvents {
+	return &StatusEvents{
+		nextID: uint64(time.Now().UnixNano()),
+		buffer: make([]StatusEvent, capacity),
+		subs:   make(map[chan StatusEvent]struct{}),
+	}
+}
+
+// Publish assigns the event an ID and delivers it to every subscriber.
+// Subscribers that are not keeping up are dropped; their channel is closed so they can resume from their last ID.
+func (e *StatusEvents) Publish(event StatusEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	event.ID = e.nextID
+	e.nextID++
+
+	if len(e.buffer) > 0 {
+		if e.size < len(e.buffer) {
+			e.buffer[(e.start+e.size)%len(e.buffer)] = event
+			e.size++
+		} else {
+			e.buffer[e.start] = event
+			e.start = (e.start + 1) % len(e.buffer)
+		}
+	}
+
+	for ch := range e.subs {
+		select {
+		case ch <- event:
+		default:
+			delete(e.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe returns the buffered events with an ID greater than after, followed by a channel receiving every
+// later event. complete is false if events after the cursor were already dropped from the buffer.
+// An after of 0 skips the backlog. Call cancel once done with the channel.
+func (e *StatusEvents) Subscribe(after uint64, bufferSize int) (backlog []StatusEvent, complete bool, events <-chan StatusEvent, cancel func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	complete = true
+	if after != 0 {
+		for i := 0; i < e.size; i++ {
+			event := e.buffer[(e.start+i)%len(e.buffer)]
+			if event.ID > after {
+				backlog = append(backlog, event)
+			}
+		}
+		// the event directly after the cursor must still be buffered (or not have happened yet)
+		if after+1 < e.nextID && (e.size == 0 || e.buffer[e.start].ID > after+1) {
+			complete = false
+		}
+	}
+
+	ch := make(chan StatusEvent, bufferSize)
+	e.subs[ch] = struct{}{}
+
+	cancel = func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		if _, ok := e.subs[ch]; ok {
+			delete(e.subs, ch)
+			close(ch)
+		}
+	}
+	return backlog, complete, ch, cancel
+}
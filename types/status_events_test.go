@@ -0,0 +1,59 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/strangelove-ventures/noble-cctp-relayer/types"
+)
+
+func TestStatusEventsResume(t *testing.T) {
+	events := types.NewStatusEvents(3)
+
+	// nothing published yet, a fresh subscriber has no backlog
+	backlog, complete, live, cancel := events.Subscribe(0, 10)
+	require.Empty(t, backlog)
+	require.True(t, complete)
+
+	for _, status := range []string{types.Created, types.Pending, types.Attested, types.Complete} {
+		events.Publish(types.StatusEvent{TxHash: "0x1", Status: status})
+	}
+
+	var ids []uint64
+	for i := 0; i < 4; i++ {
+		event := <-live
+		ids = append(ids, event.ID)
+	}
+	cancel()
+	require.Equal(t, ids[0]+3, ids[3])
+
+	// resume after the second event, both later events are still buffered
+	backlog, complete, _, cancel = events.Subscribe(ids[1], 10)
+	defer cancel()
+	require.True(t, complete)
+	require.Len(t, backlog, 2)
+	require.Equal(t, types.Attested, backlog[0].Status)
+	require.Equal(t, types.Complete, backlog[1].Status)
+
+	// the first event after the cursor was pushed out of the buffer
+	backlog, complete, _, cancel = events.Subscribe(ids[0]-1, 10)
+	defer cancel()
+	require.False(t, complete)
+	require.Len(t, backlog, 3)
+}
+
+func TestStatusEventsDropsSlowSubscribers(t *testing.T) {
+	events := types.NewStatusEvents(10)
+
+	_, _, live, cancel := events.Subscribe(0, 1)
+	defer cancel()
+
+	events.Publish(types.StatusEvent{Status: types.Created})
+	events.Publish(types.StatusEvent{Status: types.Pending})
+
+	_, ok := <-live
+	require.True(t, ok)
+	_, ok = <-live
+	require.False(t, ok)
+}